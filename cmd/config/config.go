@@ -0,0 +1,14 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ConfigCmd represents the config command
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create, inspect and validate the fcli configuration",
+	Long: `Manage the fcli config file. It is read from --config, $XDG_CONFIG_HOME/fcli/config.yaml
+(~/.config/fcli/config.yaml) or the legacy ~/.fcli-config, in that order. Every key can be
+overridden with an FCLI_* environment variable, e.g. FCLI_RADARR_URL or FCLI_RADARR_API_KEY.`,
+}
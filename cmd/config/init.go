@@ -0,0 +1,24 @@
+package config
+
+import (
+	"flashbacklabsio/fcli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var force bool
+
+// initCmd represents the config init subcommand
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively write a starter config file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.HandleInit(force)
+	},
+}
+
+func init() {
+	initCmd.Flags().BoolVar(&force, "force", false, "Replace an existing config file")
+
+	ConfigCmd.AddCommand(initCmd)
+}
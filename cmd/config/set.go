@@ -0,0 +1,22 @@
+package config
+
+import (
+	"flashbacklabsio/fcli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// setCmd represents the config set subcommand
+var setCmd = &cobra.Command{
+	Use:     "set <key> <value>",
+	Short:   "Set a single key in the config file",
+	Example: "  fcli config set radarr.apiKey 0123456789abcdef\n  fcli config set radarr.uhd.is4k true",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.HandleSet(args[0], args[1])
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(setCmd)
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"flashbacklabsio/fcli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// showCmd represents the config show subcommand
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration with API keys masked",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.HandleShow()
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(showCmd)
+}
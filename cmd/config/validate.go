@@ -0,0 +1,20 @@
+package config
+
+import (
+	"flashbacklabsio/fcli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the config validate subcommand
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the url and API key of every service by calling its status endpoint",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.HandleValidate(cmd.Context())
+	},
+}
+
+func init() {
+	ConfigCmd.AddCommand(validateCmd)
+}
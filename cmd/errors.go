@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+)
+
+// Exit codes used by fcli. Scripts can rely on these to tell failures apart.
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitUnauthorized = 3
+	ExitNotFound     = 4
+	ExitAPIError     = 5
+	ExitUnavailable  = 6
+)
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case rest.IsUnauthorized(err):
+		return ExitUnauthorized
+	case rest.IsNotFound(err):
+		return ExitNotFound
+	case rest.IsServerError(err), rest.IsUnreachable(err):
+		return ExitUnavailable
+	case rest.StatusCode(err) != 0:
+		return ExitAPIError
+	default:
+		return ExitError
+	}
+}
+
+// hint returns a suggestion on how to fix the cause of an error, or an empty string.
+func hint(err error) string {
+	var apiErr *rest.APIError
+	if !errors.As(err, &apiErr) {
+		if rest.IsUnreachable(err) {
+			return fmt.Sprintf("Check that the service is running and that its url in %s is correct (\"fcli config validate\" checks every service).", config.Path())
+		}
+		return ""
+	}
+	switch {
+	case rest.IsUnauthorized(err):
+		return fmt.Sprintf("Check the API key for %[1]s (%[1]s.apiKey in %[2]s, %[3]s_%[4]s_API_KEY or --%[1]s-api-key).", apiErr.Service, config.Path(), config.EnvPrefix, strings.ToUpper(apiErr.Service))
+	case rest.IsNotFound(err):
+		return fmt.Sprintf("Check the %[1]s url in %[2]s; it must include the API path, e.g. http://localhost:7878/api/v3.", apiErr.Service, config.Path())
+	case rest.IsServerError(err):
+		return fmt.Sprintf("%s reported an internal error; check its logs and try again later.", apiErr.Service)
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package movies
+
+import (
+	"flashbacklabsio/fcli/internal/add"
+	"flashbacklabsio/fcli/internal/movies"
+
+	"github.com/spf13/cobra"
+)
+
+var addOptions add.Options
+
+// addCmd represents the add subcommand
+var addCmd = &cobra.Command{
+	Use:   "add <tmdb-id|imdb-id|title>",
+	Short: "Add a movie to Radarr or request it through Overseer",
+	Long: `Look a movie up by TMDB ID, IMDb ID (tt...) or title through Radarr and add it. The quality
+profile and root folder come from --quality-profile and --root-folder, the radarr.qualityProfile
+and radarr.rootFolder config keys, or Radarr's only profile and folder. --search starts a search
+right away. With --overseer the movie is requested through Overseer instead, attributed to --user.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return movies.HandleAdd(cmd.Context(), radarrAPIKey, overseerAPIKey, args[0], addOptions)
+	},
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addOptions.QualityProfile, "quality-profile", "", "Quality profile name or ID")
+	addCmd.Flags().StringVar(&addOptions.RootFolder, "root-folder", "", "Root folder path or ID")
+	addCmd.Flags().BoolVar(&addOptions.Search, "search", false, "Search for the movie after adding it")
+	addCmd.Flags().BoolVar(&addOptions.Overseer, "overseer", false, "Request the movie through Overseer instead of adding it to Radarr")
+	addCmd.Flags().StringVar(&addOptions.User, "user", "", "Overseer user (name or ID) the request is made for; needs --overseer")
+
+	MoviesCmd.AddCommand(addCmd)
+}
@@ -11,8 +11,8 @@ var getCommand = &cobra.Command{
 	Use:   "get",
 	Short: "gets movies from radarr API.",
 	Long:  `Search for movies based on criteria.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		movies.HandleGet(radarrAPIKey, overseerAPIKey, limit, skip)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return movies.HandleGet(cmd.Context(), radarrAPIKey, overseerAPIKey, limit, skip)
 	},
 }
 
@@ -7,10 +7,11 @@ import (
 )
 
 var (
-	radarrAPIKey   string
-	overseerAPIKey string
-	limit          int
-	skip           int
+	radarrAPIKey    string
+	overseerAPIKey  string
+	overseerCleanup string
+	limit           int
+	skip            int
 )
 
 // MoviesCmd represents the movies command
@@ -28,5 +29,6 @@ func init() {
 	MoviesCmd.PersistentFlags().IntVar(&limit, "limit", 10, "Limit of movies to show")
 	MoviesCmd.PersistentFlags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
 	MoviesCmd.PersistentFlags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	MoviesCmd.PersistentFlags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
 	MoviesCmd.PersistentFlags().IntVar(&skip, "skip", 0, "Pagination skip. Start printing after the skip.")
 }
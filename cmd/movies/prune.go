@@ -0,0 +1,68 @@
+package movies
+
+import (
+	"fmt"
+	"time"
+
+	"flashbacklabsio/fcli/internal/movies"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addedBefore  string
+	minSizeGB    float64
+	ratingBelow  float64
+	ratingSource string
+	genres       []string
+	tags         []string
+	monitored    bool
+	hasFile      bool
+	confirm      bool
+)
+
+// pruneCmd represents the prune subcommand
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete movies matching rule-based criteria",
+	Long: `Select movies by criteria (added date, size, rating, genre, tag, monitored and file state)
+and delete them from Radarr and Overseer without prompting. By default only a report of the
+matching movies is printed; pass --yes to actually delete them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		criteria := movies.PruneCriteria{
+			MinSizeGB:    minSizeGB,
+			RatingBelow:  ratingBelow,
+			RatingSource: ratingSource,
+			Genres:       genres,
+			Tags:         tags,
+		}
+		if len(addedBefore) > 0 {
+			t, err := time.Parse("2006-01-02", addedBefore)
+			if err != nil {
+				return fmt.Errorf("invalid --added-before date %q, expected YYYY-MM-DD", addedBefore)
+			}
+			criteria.AddedBefore = t
+		}
+		if cmd.Flags().Changed("monitored") {
+			criteria.Monitored = &monitored
+		}
+		if cmd.Flags().Changed("has-file") {
+			criteria.HasFile = &hasFile
+		}
+		return movies.HandlePrune(cmd.Context(), radarrAPIKey, overseerAPIKey, overseerCleanup, criteria, confirm)
+	},
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&addedBefore, "added-before", "", "Only movies added before this date (YYYY-MM-DD)")
+	pruneCmd.Flags().Float64Var(&minSizeGB, "min-size-gb", 0, "Only movies using at least this many GB on disk")
+	pruneCmd.Flags().Float64Var(&ratingBelow, "rating-below", 0, "Only movies rated below this value (unrated movies are skipped)")
+	pruneCmd.Flags().StringVar(&ratingSource, "rating-source", "imdb", "Rating used by --rating-below: imdb, tmdb, metacritic or rottenTomatoes")
+	pruneCmd.Flags().StringSliceVar(&genres, "genre", nil, "Only movies with any of these genres")
+	pruneCmd.Flags().StringSliceVar(&tags, "tag", nil, "Only movies with any of these Radarr tag labels")
+	pruneCmd.Flags().BoolVar(&monitored, "monitored", false, "Only movies with this monitored state")
+	pruneCmd.Flags().BoolVar(&hasFile, "has-file", false, "Only movies with this file state")
+	pruneCmd.Flags().BoolVar(&confirm, "yes", false, "Delete the matching movies instead of only reporting them")
+
+	MoviesCmd.AddCommand(pruneCmd)
+}
@@ -11,8 +11,8 @@ var searchAndDeleteCmd = &cobra.Command{
 	Use:   "searchanddelete",
 	Short: "Search and delete movies",
 	Long:  `Search for movies based on criteria and delete them from the database.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		movies.HandleSearchAndDelete(radarrAPIKey, overseerAPIKey, limit, skip)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return movies.HandleSearchAndDelete(cmd.Context(), radarrAPIKey, overseerAPIKey, overseerCleanup, limit, skip)
 	},
 }
 
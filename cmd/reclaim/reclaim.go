@@ -0,0 +1,54 @@
+package reclaim
+
+import (
+	"flashbacklabsio/fcli/internal/reclaim"
+	"flashbacklabsio/fcli/internal/units"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	radarrAPIKey    string
+	sonarrAPIKey    string
+	overseerAPIKey  string
+	overseerCleanup string
+	target          string
+	weights         reclaim.Weights
+	includeAiring   bool
+	allowPartial    bool
+	confirm         bool
+)
+
+// ReclaimCmd represents the reclaim command
+var ReclaimCmd = &cobra.Command{
+	Use:   "reclaim",
+	Short: "Plan and apply deletions that free a target amount of disk space",
+	Long: `Rank movies and individual seasons by size, age and rating, and build a deletion plan
+that frees at least the requested amount of space, e.g. "fcli reclaim --target 500GB".
+The plan is printed with cumulative savings and applied after one confirmation (or --yes).
+A target larger than everything on disk is an error unless --allow-partial is given, since
+that plan would delete the whole library. Specials are never planned, and neither are the
+seasons of continuing series that are still airing unless --include-airing is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bytes, err := units.ParseBytes(target)
+		if err != nil {
+			return err
+		}
+		return reclaim.HandleReclaim(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup, bytes, weights, includeAiring, allowPartial, confirm)
+	},
+}
+
+func init() {
+	ReclaimCmd.Flags().StringVar(&target, "target", "", "Amount of space to free, e.g. 500GB or 1.5TB")
+	ReclaimCmd.Flags().Float64Var(&weights.Size, "weight-size", 0.5, "Weight of the size signal when ranking candidates")
+	ReclaimCmd.Flags().Float64Var(&weights.Age, "weight-age", 0.3, "Weight of the age signal when ranking candidates")
+	ReclaimCmd.Flags().Float64Var(&weights.Rating, "weight-rating", 0.2, "Weight of the (low) rating signal when ranking candidates")
+	ReclaimCmd.Flags().BoolVar(&includeAiring, "include-airing", false, "Also plan seasons of continuing series that are still airing")
+	ReclaimCmd.Flags().BoolVar(&allowPartial, "allow-partial", false, "Apply the plan even when it cannot reach the target, deleting everything it lists")
+	ReclaimCmd.Flags().BoolVar(&confirm, "yes", false, "Apply the plan without asking for confirmation")
+	ReclaimCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	ReclaimCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	ReclaimCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	ReclaimCmd.Flags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
+	ReclaimCmd.MarkFlagRequired("target")
+}
@@ -0,0 +1,37 @@
+package reconcile
+
+import (
+	"flashbacklabsio/fcli/internal/reconcile"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	radarrAPIKey   string
+	sonarrAPIKey   string
+	overseerAPIKey string
+	clearStale     bool
+	markAvailable  bool
+)
+
+// ReconcileCmd represents the reconcile command
+var ReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Find titles Overseer and Radarr/Sonarr disagree about",
+	Long: `Compare Overseer's media with the Radarr and Sonarr libraries in both directions. Reports titles
+Overseer shows as available that are gone from disk, titles on disk that Overseer doesn't show as
+available and titles Overseer doesn't know at all. Afterwards it offers to clear the stale Overseer
+media and to mark the titles on disk as available; --clear-stale and --mark-available apply those
+fixes without asking.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reconcile.HandleReconcile(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, clearStale, markAvailable)
+	},
+}
+
+func init() {
+	ReconcileCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	ReconcileCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	ReconcileCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	ReconcileCmd.Flags().BoolVar(&clearStale, "clear-stale", false, "Clear stale Overseer media without asking")
+	ReconcileCmd.Flags().BoolVar(&markAvailable, "mark-available", false, "Mark titles on disk as available in Overseer without asking")
+}
@@ -0,0 +1,12 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ReportCmd represents the report command
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports about how the library uses disk space",
+	Long:  `Read-only reports that join Overseer requests with the Radarr and Sonarr libraries.`,
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"flashbacklabsio/fcli/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	radarrAPIKey   string
+	sonarrAPIKey   string
+	overseerAPIKey string
+	top            int
+)
+
+// requestersCmd represents the requesters subcommand
+var requestersCmd = &cobra.Command{
+	Use:   "requesters",
+	Short: "Show how much disk space each Overseer user's requests take up",
+	Long: `Join every Overseer request with the Radarr movie (by TMDB ID) or Sonarr series (by TVDB or
+TMDB ID) it was downloaded as, and total the size, item count and request age per user, largest
+first. Items requested by several users count for each of them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return report.HandleRequesters(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, top)
+	},
+}
+
+func init() {
+	requestersCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	requestersCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	requestersCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	requestersCmd.Flags().IntVar(&top, "top", 3, "Number of largest items to list per user")
+
+	ReportCmd.AddCommand(requestersCmd)
+}
@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	expireDelete  bool
+	expireConfirm bool
+)
+
+// expiringCmd represents the expiring subcommand
+var expiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List titles that expire a while after they became available",
+	Long: `List the movies and series tagged for expiry and when they are due. A title expires when it or
+one of its requests carries the Radarr/Sonarr tag "expire" (expiry.tag) and it has been available
+for longer than 30 days (expiry.ttl); a tag like "expire-7d" sets its own time to live.
+With --delete the due titles are deleted like any other deletion, after one confirmation (or --yes).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return requests.HandleExpiring(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup, expireDelete, expireConfirm)
+	},
+}
+
+func init() {
+	expiringCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	expiringCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	expiringCmd.Flags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
+	expiringCmd.Flags().BoolVar(&expireDelete, "delete", false, "Delete the titles that are due")
+	expiringCmd.Flags().BoolVar(&expireConfirm, "yes", false, "Delete without asking for confirmation")
+
+	RequestsCmd.AddCommand(expiringCmd)
+}
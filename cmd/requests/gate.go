@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+
+	"github.com/spf13/cobra"
+)
+
+var gateOpts requests.GateOptions
+
+// gateCmd represents the gate subcommand
+var gateCmd = &cobra.Command{
+	Use:   "gate",
+	Short: "Approve pending requests that fit on disk",
+	Long: `Estimate the size of every pending request from its runtime and the largest size its Radarr or
+Sonarr quality profile allows, and approve the requests that fit in the free space of their root
+folder's disk, oldest first. The rest are held, or declined with --decline.
+--min-free (gate.minFree) keeps space free on every disk. Requests are only reported unless --yes
+is given; with --every the gate runs again at that interval until interrupted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return requests.HandleGate(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, gateOpts)
+	},
+}
+
+func init() {
+	gateCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	gateCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	gateCmd.Flags().StringVar(&gateOpts.MinFree, "min-free", "", "Space to keep free on every disk, e.g. 100GB (default: gate.minFree from the config, else none)")
+	gateCmd.Flags().BoolVar(&gateOpts.Decline, "decline", false, "Decline the requests that don't fit instead of leaving them pending")
+	gateCmd.Flags().StringVar(&gateOpts.Reason, "reason", "", "Reason printed for declined requests; Overseer doesn't pass it on to users (default: the space missing)")
+	gateCmd.Flags().BoolVar(&gateOpts.Confirm, "yes", false, "Approve and decline the requests instead of only reporting them")
+	gateCmd.Flags().DurationVar(&gateOpts.Every, "every", 0, "Run again at this interval, e.g. 15m, until interrupted")
+
+	RequestsCmd.AddCommand(gateCmd)
+}
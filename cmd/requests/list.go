@@ -0,0 +1,26 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Overseer requests",
+	Long: `List Overseer requests, newest first. Titles and sizes come from Radarr and Sonarr when
+they are configured.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := filter(cmd)
+		if err != nil {
+			return err
+		}
+		return requests.HandleList(cmd.Context(), overseerAPIKey, f)
+	},
+}
+
+func init() {
+	RequestsCmd.AddCommand(listCmd)
+}
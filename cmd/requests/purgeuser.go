@@ -0,0 +1,39 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	radarrAPIKey    string
+	sonarrAPIKey    string
+	overseerCleanup string
+	includeOngoing  bool
+	purgeConfirm    bool
+)
+
+// purgeUserCmd represents the purge-user subcommand
+var purgeUserCmd = &cobra.Command{
+	Use:   "purge-user <username|id>",
+	Short: "Delete everything an Overseer user requested",
+	Long: `Collect every Overseer request of a user, resolve them to Radarr movies and Sonarr series and
+delete those together with the requests. Items other users also requested are kept (only the
+user's requests are deleted), as are series that are still airing unless --include-ongoing is set.
+The plan is shown with its total size and applied after one confirmation (or --yes).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return requests.HandlePurgeUser(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup, args[0], includeOngoing, purgeConfirm)
+	},
+}
+
+func init() {
+	purgeUserCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	purgeUserCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	purgeUserCmd.Flags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
+	purgeUserCmd.Flags().BoolVar(&includeOngoing, "include-ongoing", false, "Also delete series that are still airing")
+	purgeUserCmd.Flags().BoolVar(&purgeConfirm, "yes", false, "Apply the plan without asking for confirmation")
+
+	RequestsCmd.AddCommand(purgeUserCmd)
+}
@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	quotaDecline bool
+	quotaConfirm bool
+	quotaReason  string
+)
+
+// quotaCmd represents the quota subcommand
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Report users over their storage budget",
+	Long: `Report how much disk space the requests of every Overseer user take up against their storage
+budget. Budgets come from quota.default (e.g. "500GB") and quota.users, which sets the budget of
+single users by ID or name; "unlimited" exempts a user.
+With --decline the pending requests of users over budget are declined after one confirmation (or
+--yes). The reason is only printed; Overseer has no way to pass it on to the users.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return requests.HandleQuota(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, quotaDecline, quotaConfirm, quotaReason)
+	},
+}
+
+func init() {
+	quotaCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	quotaCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	quotaCmd.Flags().BoolVar(&quotaDecline, "decline", false, "Decline the pending requests of users over budget")
+	quotaCmd.Flags().BoolVar(&quotaConfirm, "yes", false, "Decline without asking for confirmation")
+	quotaCmd.Flags().StringVar(&quotaReason, "reason", "", "Reason printed for declined requests; Overseer doesn't pass it on to users (default: the storage they use and their budget)")
+
+	RequestsCmd.AddCommand(quotaCmd)
+}
@@ -0,0 +1,94 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/requests"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	overseerAPIKey string
+	statuses       []string
+	user           string
+	mediaType      string
+	is4K           bool
+	olderThan      string
+)
+
+// RequestsCmd represents the requests command
+var RequestsCmd = &cobra.Command{
+	Use:   "requests",
+	Short: "List and manage Overseer requests",
+	Long: `List, approve, decline, retry and delete Overseer requests. Requests can be selected by ID
+or with the --status, --user, --type, --4k and --older-than filters.`,
+}
+
+// filter builds the request filter from the persistent flags.
+func filter(cmd *cobra.Command) (requests.Filter, error) {
+	f := requests.Filter{Statuses: statuses, User: user, MediaType: mediaType}
+	if cmd.Flags().Changed("4k") {
+		f.Is4K = &is4K
+	}
+	if len(olderThan) > 0 {
+		age, err := units.ParseAge(olderThan)
+		if err != nil {
+			return f, err
+		}
+		f.OlderThan = age
+	}
+	return f, nil
+}
+
+// parseIDs parses request IDs given as arguments.
+func parseIDs(args []string) ([]int, error) {
+	var ids []int
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// actionCommand builds the command for a request action.
+func actionCommand(action requests.Action, short string) *cobra.Command {
+	var confirm bool
+	cmd := &cobra.Command{
+		Use:   action.Name + " [request IDs...]",
+		Short: short,
+		Long: short + `. Requests are given by ID or selected with filters; requests selected by
+filters are only listed unless --yes is set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			f, err := filter(cmd)
+			if err != nil {
+				return err
+			}
+			return requests.HandleAction(cmd.Context(), overseerAPIKey, action, ids, f, confirm)
+		},
+	}
+	cmd.Flags().BoolVar(&confirm, "yes", false, "Apply the action to the requests selected by filters instead of only listing them")
+	return cmd
+}
+
+func init() {
+	RequestsCmd.PersistentFlags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	RequestsCmd.PersistentFlags().StringSliceVar(&statuses, "status", nil, "Only requests with any of these statuses: pending, approved, declined, failed or completed")
+	RequestsCmd.PersistentFlags().StringVar(&user, "user", "", "Only requests made by this user (ID, username, display name or email)")
+	RequestsCmd.PersistentFlags().StringVar(&mediaType, "type", "", "Only requests for this media type: movie or tv")
+	RequestsCmd.PersistentFlags().BoolVar(&is4K, "4k", false, "Only 4K requests (--4k=false for regular requests)")
+	RequestsCmd.PersistentFlags().StringVar(&olderThan, "older-than", "", "Only requests made longer ago than this, e.g. 30d, 2w or 1y")
+
+	RequestsCmd.AddCommand(actionCommand(requests.Approve, "Approve pending requests"))
+	RequestsCmd.AddCommand(actionCommand(requests.Decline, "Decline pending requests"))
+	RequestsCmd.AddCommand(actionCommand(requests.Retry, "Retry failed requests"))
+	RequestsCmd.AddCommand(actionCommand(requests.Delete, "Delete requests"))
+}
@@ -1,31 +1,92 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
+	configcmd "flashbacklabsio/fcli/cmd/config"
 	"flashbacklabsio/fcli/cmd/movies"
+	"flashbacklabsio/fcli/cmd/reclaim"
+	"flashbacklabsio/fcli/cmd/reconcile"
+	"flashbacklabsio/fcli/cmd/report"
+	"flashbacklabsio/fcli/cmd/requests"
 	"flashbacklabsio/fcli/cmd/series"
+	"flashbacklabsio/fcli/cmd/tui"
 	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dryRun     bool
+	configFile string
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "fcli",
-	Short: "fcli is a CLI tool for flashbacklabsio",
-	Long:  `A CLI tool for managing different services and commands for flashbacklabsio.`,
+	Use:           "fcli",
+	Short:         "fcli is a CLI tool for flashbacklabsio",
+	Long:          `A CLI tool for managing different services and commands for flashbacklabsio.`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags were parsed successfully, so later errors aren't usage errors.
+		cmd.SilenceUsage = true
+		if err := config.InitConfig(configFile); err != nil {
+			return err
+		}
+		if dryRun {
+			plan.Enable()
+		}
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if p := plan.Active(); p != nil {
+			fmt.Println()
+			p.Print(os.Stdout)
+		}
+	},
 }
 
 // Execute runs the root command
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// Cancel in-flight requests when the user interrupts fcli.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if h := hint(err); h != "" {
+			fmt.Fprintln(os.Stderr, h)
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	// Add subcommands here
-	config.InitConfig()
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default $XDG_CONFIG_HOME/fcli/config.yaml, falling back to ~/.fcli-config)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the changes that would be made without calling any write endpoints")
+	rootCmd.PersistentFlags().StringP("output", "o", render.Table, "Output format for listings: "+strings.Join(render.Formats, "|"))
+	rootCmd.PersistentFlags().StringSlice("columns", nil, "Comma-separated fields to show in listings, e.g. title,sizeOnDisk,path")
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("columns", rootCmd.PersistentFlags().Lookup("columns"))
+	rootCmd.PersistentFlags().StringSlice("instance", nil, "Only use these named Radarr/Sonarr instances, e.g. uhd or default,uhd (all configured instances by default)")
+	viper.BindPFlag("instance", rootCmd.PersistentFlags().Lookup("instance"))
+	rootCmd.PersistentFlags().String("record", "", "Save every HTTP exchange with Radarr, Sonarr and Overseer as fixtures in this directory (API keys are stripped)")
+	rootCmd.PersistentFlags().String("replay", "", "Answer HTTP requests from fixtures recorded with --record instead of contacting the services")
+	viper.BindPFlag("record", rootCmd.PersistentFlags().Lookup("record"))
+	viper.BindPFlag("replay", rootCmd.PersistentFlags().Lookup("replay"))
 	rootCmd.AddCommand(movies.MoviesCmd)
 	rootCmd.AddCommand(series.SeriesCommand)
+	rootCmd.AddCommand(reclaim.ReclaimCmd)
+	rootCmd.AddCommand(requests.RequestsCmd)
+	rootCmd.AddCommand(report.ReportCmd)
+	rootCmd.AddCommand(reconcile.ReconcileCmd)
+	rootCmd.AddCommand(tui.TuiCmd)
+	rootCmd.AddCommand(configcmd.ConfigCmd)
 }
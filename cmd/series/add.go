@@ -0,0 +1,37 @@
+package series
+
+import (
+	"flashbacklabsio/fcli/internal/add"
+	"flashbacklabsio/fcli/internal/series"
+
+	"github.com/spf13/cobra"
+)
+
+var addOptions add.Options
+
+// addCmd represents the add subcommand
+var addCmd = &cobra.Command{
+	Use:   "add <tvdb-id|title>",
+	Short: "Add a series to Sonarr or request it through Overseer",
+	Long: `Look a series up by TVDB ID or title through Sonarr and add it with every season except specials
+monitored. The quality profile and root folder come from --quality-profile and --root-folder, the
+sonarr.qualityProfile and sonarr.rootFolder config keys, or Sonarr's only profile and folder.
+--search starts searching for missing episodes right away. With --overseer all seasons are
+requested through Overseer instead, attributed to --user.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return series.HandleAdd(cmd.Context(), sonarrAPIKey, overseerAPIKey, args[0], addOptions)
+	},
+}
+
+func init() {
+	addCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	addCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	addCmd.Flags().StringVar(&addOptions.QualityProfile, "quality-profile", "", "Quality profile name or ID")
+	addCmd.Flags().StringVar(&addOptions.RootFolder, "root-folder", "", "Root folder path or ID")
+	addCmd.Flags().BoolVar(&addOptions.Search, "search", false, "Search for missing episodes after adding the series")
+	addCmd.Flags().BoolVar(&addOptions.Overseer, "overseer", false, "Request the series through Overseer instead of adding it to Sonarr")
+	addCmd.Flags().StringVar(&addOptions.User, "user", "", "Overseer user (name or ID) the request is made for; needs --overseer")
+
+	SeriesCommand.AddCommand(addCmd)
+}
@@ -0,0 +1,33 @@
+package series
+
+import (
+	"flashbacklabsio/fcli/internal/series"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	getLimit   int
+	getSkip    int
+	getSeasons bool
+)
+
+// getCmd represents the get subcommand
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "gets series from sonarr API.",
+	Long: `List series sorted by size on disk. Ended, continuing and upcoming shows are marked in the AIRING column.
+Use --seasons to show the size, episode files, completeness and monitoring of every season.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return series.HandleGet(cmd.Context(), sonarrAPIKey, getLimit, getSkip, getSeasons)
+	},
+}
+
+func init() {
+	getCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	getCmd.Flags().IntVar(&getLimit, "limit", 10, "Limit of series to show")
+	getCmd.Flags().IntVar(&getSkip, "skip", 0, "Pagination skip. Start printing after the skip.")
+	getCmd.Flags().BoolVar(&getSeasons, "seasons", false, "Show the seasons of every series")
+
+	SeriesCommand.AddCommand(getCmd)
+}
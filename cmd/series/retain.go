@@ -0,0 +1,54 @@
+package series
+
+import (
+	"flashbacklabsio/fcli/internal/series"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	keepSeasons     int
+	includeSpecials bool
+	keepEpisodes    int
+	keepDays        int
+	windowBy        string
+	confirm         bool
+)
+
+// retainCmd represents the retain subcommand
+var retainCmd = &cobra.Command{
+	Use:   "retain",
+	Short: "Keep only the most recent seasons or episodes of every series",
+	Long: `With --keep-seasons, delete the episode files of all but the newest seasons of every series and
+unmonitor those seasons. Specials (season 0) are left alone unless --include-specials is set, and
+series whose newest season is still airing are skipped.
+
+With --keep-episodes or --keep-days, keep a rolling window of episode files of every daily series
+and delete the older ones, dated by air date or by --by dateAdded. Their episodes are unmonitored
+so they are not downloaded again.
+
+By default only a report is printed; pass --yes to actually delete the files. The command never
+prompts, so it can be scheduled.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := series.RetainPolicy{
+			KeepSeasons:     keepSeasons,
+			IncludeSpecials: includeSpecials,
+			KeepEpisodes:    keepEpisodes,
+			KeepDays:        keepDays,
+			By:              windowBy,
+		}
+		return series.HandleRetain(cmd.Context(), sonarrAPIKey, policy, confirm)
+	},
+}
+
+func init() {
+	retainCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	retainCmd.Flags().IntVar(&keepSeasons, "keep-seasons", 0, "Number of most recent seasons to keep")
+	retainCmd.Flags().BoolVar(&includeSpecials, "include-specials", false, "Also delete specials (season 0)")
+	retainCmd.Flags().IntVar(&keepEpisodes, "keep-episodes", 0, "Number of newest episode files to keep per daily series")
+	retainCmd.Flags().IntVar(&keepDays, "keep-days", 0, "Keep the episode files of daily series from the last this many days")
+	retainCmd.Flags().StringVar(&windowBy, "by", series.ByAirDate, "Date the episode window is based on: airDate or dateAdded")
+	retainCmd.Flags().BoolVar(&confirm, "yes", false, "Delete the files instead of only reporting them")
+
+	SeriesCommand.AddCommand(retainCmd)
+}
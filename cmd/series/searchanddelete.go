@@ -7,27 +7,32 @@ import (
 )
 
 var (
-	sonarrAPIKey   string
-	overseerAPIKey string
-	limit          int
+	sonarrAPIKey    string
+	overseerAPIKey  string
+	overseerCleanup string
+	limit           int
+	skip            int
 )
 
 // searchAndDeleteCmd represents the searchanddelete subcommand
 var searchAndDeleteCmd = &cobra.Command{
 	Use:   "searchanddelete",
 	Short: "Search and delete shows/series",
-	Long:  `Search for movies based on criteria and delete them from the database.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		series.HandleSearchAndDeleteSeries(sonarrAPIKey, overseerAPIKey, limit)
+	Long: `List series by size and delete several of them at once: whole series, several seasons or
+individual episode files of a season.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return series.HandleSearchAndDeleteSeries(cmd.Context(), sonarrAPIKey, overseerAPIKey, overseerCleanup, limit, skip)
 	},
 }
 
 func init() {
 	// Set default values to environment variables or fallback to empty strings
 
-	searchAndDeleteCmd.Flags().StringVar(&sonarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	searchAndDeleteCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
 	searchAndDeleteCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
-	searchAndDeleteCmd.Flags().IntVar(&limit, "limit", 10, "Limit of movies to show")
+	searchAndDeleteCmd.Flags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
+	searchAndDeleteCmd.Flags().IntVar(&limit, "limit", 10, "Limit of series to show")
+	searchAndDeleteCmd.Flags().IntVar(&skip, "skip", 0, "Pagination skip. Start printing after the skip.")
 
 	SeriesCommand.AddCommand(searchAndDeleteCmd)
 }
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"flashbacklabsio/fcli/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	radarrAPIKey    string
+	sonarrAPIKey    string
+	overseerAPIKey  string
+	overseerCleanup string
+)
+
+// TuiCmd represents the tui command
+var TuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and delete movies, series and requests in a full-screen terminal UI",
+	Long: `Open a full-screen terminal UI with tabs for Movies, Series and Requests. Columns can be
+sorted and filtered, series can be opened to their seasons and episode files, and several
+items can be selected at once. Everything selected is deleted after a single confirmation.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tui.HandleTUI(cmd.Context(), radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup)
+	},
+}
+
+func init() {
+	TuiCmd.Flags().StringVar(&radarrAPIKey, "radarr-api-key", "", "API key for Radarr")
+	TuiCmd.Flags().StringVar(&sonarrAPIKey, "sonarr-api-key", "", "API key for Sonarr")
+	TuiCmd.Flags().StringVar(&overseerAPIKey, "overseer-api-key", "", "API key for Overseer")
+	TuiCmd.Flags().StringVar(&overseerCleanup, "overseer-cleanup", "", "How to clean up Overseer after deletions: delete-media, delete-requests-only or reset-status (default: overseer.cleanup from the config, else delete-media)")
+}
@@ -0,0 +1,111 @@
+package add
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// Options are the settings for adding a movie or series. An empty QualityProfile or RootFolder
+// falls back to the instance's configured default, or to the only one Radarr or Sonarr has.
+type Options struct {
+	QualityProfile string
+	RootFolder     string
+	Search         bool
+	// Overseer requests the title through Overseer instead of adding it directly, attributed
+	// to User, or to the owner of the API key when User is empty.
+	Overseer bool
+	User     string
+}
+
+// Choice is a quality profile or root folder offered by Radarr or Sonarr. Root folders are
+// named by their path.
+type Choice struct {
+	ID   int
+	Name string
+}
+
+// Resolve picks the quality profile and root folder for opts on instance of service, e.g.
+// "Radarr". Adding a title directly needs both; a request through Overseer only sets the ones
+// that were given and leaves the rest to Overseer.
+func Resolve(service string, instance config.Instance, profiles, folders []Choice, opts Options) (int, string, error) {
+	if opts.QualityProfile == "" {
+		opts.QualityProfile = instance.QualityProfile
+	}
+	if opts.RootFolder == "" {
+		opts.RootFolder = instance.RootFolder
+	}
+	var profileID int
+	var rootFolder string
+	if !opts.Overseer || opts.QualityProfile != "" {
+		profile, err := pick(service, "quality profile", "qualityProfile", profiles, opts.QualityProfile, strings.EqualFold)
+		if err != nil {
+			return 0, "", err
+		}
+		profileID = profile.ID
+	}
+	if !opts.Overseer || opts.RootFolder != "" {
+		samePath := func(a, b string) bool { return strings.TrimRight(a, "/") == strings.TrimRight(b, "/") }
+		folder, err := pick(service, "root folder", "rootFolder", folders, opts.RootFolder, samePath)
+		if err != nil {
+			return 0, "", err
+		}
+		rootFolder = folder.Name
+	}
+	return profileID, rootFolder, nil
+}
+
+// pick finds the choice a name or ID names. Without one, the only choice there is is used.
+// key is the instance setting that sets the default.
+func pick(service, what, key string, choices []Choice, wanted string, same func(a, b string) bool) (Choice, error) {
+	var names []string
+	for _, choice := range choices {
+		if wanted != "" && (same(choice.Name, wanted) || strconv.Itoa(choice.ID) == wanted) {
+			return choice, nil
+		}
+		names = append(names, choice.Name)
+	}
+	if wanted == "" && len(choices) == 1 {
+		return choices[0], nil
+	}
+	if wanted == "" {
+		flag := strings.ReplaceAll(what, " ", "-")
+		return Choice{}, fmt.Errorf("pick a %s with --%s or %s.%s (available: %s)", what, flag, strings.ToLower(service), key, strings.Join(names, ", "))
+	}
+	return Choice{}, fmt.Errorf("no %s %q in %s (available: %s)", what, wanted, service, strings.Join(names, ", "))
+}
+
+// Request creates an Overseer request for a title, for user or, when user is empty, for the
+// owner of the API key.
+func Request(ctx context.Context, client *overseer.OverseerClient, request overseer.NewRequest, title, user string) error {
+	requester := "the owner of the API key"
+	if user != "" {
+		found, err := client.FindUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		request.UserID = found.ID
+		requester = found.Name()
+	}
+	if _, err := client.CreateRequest(ctx, request); err != nil {
+		return err
+	}
+	kind := "movie"
+	if request.MediaType == "tv" {
+		kind = "series"
+	}
+	plan.Annotate(fmt.Sprintf("Request %s '%s' in Overseer for %s", kind, title, requester), 0)
+	fmt.Printf(Green+"'%s' was requested in Overseer for %s.\n"+Reset, title, requester)
+	return nil
+}
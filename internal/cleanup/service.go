@@ -0,0 +1,305 @@
+package cleanup
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// Strategies for cleaning up Overseer after a title was deleted from Radarr or Sonarr.
+const (
+	// DeleteMedia deletes the media item, or only the requests sent to the instance while the
+	// other (regular or 4K) server still uses it. This is the default.
+	DeleteMedia = "delete-media"
+	// DeleteRequestsOnly deletes the requests sent to the instance and keeps the media item.
+	DeleteRequestsOnly = "delete-requests-only"
+	// ResetStatus keeps the media item and its request history and resets the status for the
+	// instance's server to unknown, so the title can be requested again.
+	ResetStatus = "reset-status"
+)
+
+// Strategies lists the valid cleanup strategies.
+var Strategies = []string{DeleteMedia, DeleteRequestsOnly, ResetStatus}
+
+// ParseStrategy checks a cleanup strategy; an empty one is DeleteMedia.
+func ParseStrategy(strategy string) (string, error) {
+	if strategy == "" {
+		return DeleteMedia, nil
+	}
+	for _, s := range Strategies {
+		if strings.EqualFold(s, strategy) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid Overseer cleanup strategy %q, expected one of %s", strategy, strings.Join(Strategies, ", "))
+}
+
+// Overseer holds the media and requests known to Overseer, fetched once per run, and
+// removes what belongs to items deleted from a Radarr or Sonarr instance.
+type Overseer struct {
+	Client   *overseer.OverseerClient
+	Media    []overseer.Media
+	Requests []overseer.Request
+	// Strategy is how Remove and RemoveSeasons clean up, one of Strategies.
+	Strategy string
+
+	// removed tracks media deleted during this run, e.g. when the same title is deleted
+	// from a regular and a 4K instance.
+	removed map[int]bool
+	// removedRequests tracks requests deleted on their own during this run.
+	removedRequests map[int]bool
+}
+
+// Load fetches all media and requests from Overseer. strategy is one of Strategies, or empty for DeleteMedia.
+func Load(ctx context.Context, client *overseer.OverseerClient, strategy string) (*Overseer, error) {
+	strategy, err := ParseStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	media, err := client.GetMedia(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := client.GetRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Overseer{Client: client, Media: media, Requests: requests, Strategy: strategy, removed: map[int]bool{}, removedRequests: map[int]bool{}}, nil
+}
+
+// SentTo reports whether a request was sent to the given instance. A nil ServerID matches
+// any server with the same 4K setting.
+func SentTo(request overseer.Request, instance config.Instance) bool {
+	if request.Is4K != instance.Is4K {
+		return false
+	}
+	return instance.ServerID == nil || request.ServerID == *instance.ServerID
+}
+
+// Remove cleans up Overseer after title was deleted from instance, following o.Strategy. With
+// DeleteMedia the media item is deleted when nothing else refers to it; when the other (4K or
+// regular) server still has the title or has requests for it, only the requests sent to
+// instance are deleted.
+func (o *Overseer) Remove(ctx context.Context, instance config.Instance, media overseer.Media, title string) {
+	if o.removed[media.Id] {
+		return
+	}
+	if o.Strategy == ResetStatus {
+		o.resetStatus(ctx, instance, media, title)
+		return
+	}
+
+	var own []overseer.Request
+	shared := false
+	for _, request := range o.Requests {
+		if request.Media.Id != media.Id {
+			continue
+		}
+		if SentTo(request, instance) {
+			own = append(own, request)
+		} else {
+			shared = true
+		}
+	}
+	otherStatus := media.Status4k
+	if instance.Is4K {
+		otherStatus = media.Status
+	}
+	if otherStatus > overseer.MediaStatusUnknown || o.Strategy == DeleteRequestsOnly {
+		shared = true
+	}
+
+	if !shared {
+		if err := o.Client.DeleteMedia(ctx, media.Id); rest.IsNotFound(err) {
+			fmt.Printf(Yellow+"Request '%v' was already removed from Overseer.\n"+Reset, title)
+		} else if err != nil {
+			fmt.Println(Red + err.Error() + Reset)
+		} else {
+			o.removed[media.Id] = true
+			plan.Annotate(fmt.Sprintf("Delete Overseer media for '%s'", title), 0)
+			fmt.Printf(Green+"Request '%v' was successfully deleted from Overseer.\n"+Reset, title)
+		}
+		return
+	}
+
+	if len(own) == 0 && o.Strategy == DeleteRequestsOnly {
+		fmt.Printf(Yellow+"'%v' has no requests for instance '%s'; Overseer was left unchanged.\n"+Reset, title, instance.Name)
+		return
+	}
+	if len(own) == 0 {
+		fmt.Printf(Yellow+"'%v' is still used by another server in Overseer and has no requests for instance '%s'; Overseer was left unchanged.\n"+Reset, title, instance.Name)
+		return
+	}
+	for _, request := range own {
+		o.RemoveRequest(ctx, request, title)
+	}
+}
+
+// resetStatus sets the status of title for the server of instance to unknown and keeps its requests.
+func (o *Overseer) resetStatus(ctx context.Context, instance config.Instance, media overseer.Media, title string) {
+	status := media.Status
+	if instance.Is4K {
+		status = media.Status4k
+	}
+	if status <= overseer.MediaStatusUnknown {
+		fmt.Printf(Yellow+"'%v' is not available in Overseer; Overseer was left unchanged.\n"+Reset, title)
+		return
+	}
+	if err := o.Client.SetMediaStatus(ctx, media.Id, "unknown", instance.Is4K); rest.IsNotFound(err) {
+		fmt.Printf(Yellow+"Request '%v' was already removed from Overseer.\n"+Reset, title)
+	} else if err != nil {
+		fmt.Println(Red + err.Error() + Reset)
+	} else {
+		plan.Annotate(fmt.Sprintf("Reset Overseer status of '%s'", title), 0)
+		fmt.Printf(Green+"Overseer status of '%v' was reset; its requests were kept.\n"+Reset, title)
+	}
+}
+
+// Removed reports whether a request was deleted during this run, on its own or with its media.
+func (o *Overseer) Removed(request overseer.Request) bool {
+	return o.removed[request.Media.Id] || o.removedRequests[request.ID]
+}
+
+// RemoveRequest deletes a single request for title.
+func (o *Overseer) RemoveRequest(ctx context.Context, request overseer.Request, title string) {
+	if err := o.Client.DeleteRequest(ctx, request.ID); rest.IsNotFound(err) {
+		fmt.Printf(Yellow+"Request %d for '%v' was already removed from Overseer.\n"+Reset, request.ID, title)
+	} else if err != nil {
+		fmt.Println(Red + err.Error() + Reset)
+	} else {
+		o.removedRequests[request.ID] = true
+		plan.Annotate(fmt.Sprintf("Delete Overseer request %d for '%s'", request.ID, title), 0)
+		fmt.Printf(Green+"Request %d for '%v' was successfully deleted from Overseer.\n"+Reset, request.ID, title)
+	}
+}
+
+// RemoveSeasons updates Overseer after seasons of title were deleted from instance. The seasons are
+// dropped from the requests sent to instance so they can be requested again, and requests left
+// without seasons are deleted. A title that no longer has every season is marked as partially
+// available, or as unknown when none of its other seasons are available. Overseer has no endpoint
+// for the status of a single season; its next Sonarr sync resets the deleted seasons.
+// ResetStatus keeps the requests and DeleteRequestsOnly leaves the status unchanged.
+func (o *Overseer) RemoveSeasons(ctx context.Context, instance config.Instance, media overseer.Media, title string, seasonNumbers []int) {
+	if o.removed[media.Id] || len(seasonNumbers) == 0 {
+		return
+	}
+	deleted := map[int]bool{}
+	for _, seasonNumber := range seasonNumbers {
+		deleted[seasonNumber] = true
+	}
+
+	if o.Strategy != ResetStatus {
+		o.dropSeasons(ctx, instance, media, title, deleted)
+	}
+
+	status := media.Status
+	if instance.Is4K {
+		status = media.Status4k
+	}
+	if status < overseer.MediaStatusPartiallyAvailable || o.Strategy == DeleteRequestsOnly {
+		return
+	}
+	newStatus, newName := overseer.MediaStatusUnknown, "unknown"
+	for _, season := range media.Seasons {
+		seasonStatus := season.Status
+		if instance.Is4K {
+			seasonStatus = season.Status4k
+		}
+		if !deleted[season.SeasonNumber] && seasonStatus >= overseer.MediaStatusPartiallyAvailable {
+			newStatus, newName = overseer.MediaStatusPartiallyAvailable, "partial"
+			break
+		}
+	}
+	// Without season details Overseer can't tell which seasons are left, so keep the title visible.
+	if len(media.Seasons) == 0 {
+		newStatus, newName = overseer.MediaStatusPartiallyAvailable, "partial"
+	}
+	if status != newStatus {
+		if err := o.Client.SetMediaStatus(ctx, media.Id, newName, instance.Is4K); err != nil {
+			fmt.Println(Red + err.Error() + Reset)
+			return
+		}
+		plan.Annotate(fmt.Sprintf("Mark '%s' as %s in Overseer", title, overseer.MediaStatusName(newStatus)), 0)
+		fmt.Printf(Green+"'%v' is now %s in Overseer.\n"+Reset, title, overseer.MediaStatusName(newStatus))
+	}
+
+	// Keep the loaded media in step for later deletions in this run.
+	for i := range o.Media {
+		if o.Media[i].Id != media.Id {
+			continue
+		}
+		for j := range o.Media[i].Seasons {
+			if !deleted[o.Media[i].Seasons[j].SeasonNumber] {
+				continue
+			}
+			if instance.Is4K {
+				o.Media[i].Seasons[j].Status4k = overseer.MediaStatusUnknown
+			} else {
+				o.Media[i].Seasons[j].Status = overseer.MediaStatusUnknown
+			}
+		}
+		if instance.Is4K {
+			o.Media[i].Status4k = newStatus
+		} else {
+			o.Media[i].Status = newStatus
+		}
+	}
+}
+
+// dropSeasons removes deleted seasons from the requests for media sent to instance.
+func (o *Overseer) dropSeasons(ctx context.Context, instance config.Instance, media overseer.Media, title string, deleted map[int]bool) {
+	for i, request := range o.Requests {
+		if request.Media.Id != media.Id || !SentTo(request, instance) || o.removedRequests[request.ID] {
+			continue
+		}
+		var kept, dropped []int
+		var keptSeasons []overseer.SeasonRequest
+		for _, season := range request.Seasons {
+			if deleted[season.SeasonNumber] {
+				dropped = append(dropped, season.SeasonNumber)
+			} else {
+				kept = append(kept, season.SeasonNumber)
+				keptSeasons = append(keptSeasons, season)
+			}
+		}
+		if len(dropped) == 0 {
+			continue
+		}
+		if len(kept) == 0 {
+			o.RemoveRequest(ctx, request, title)
+			continue
+		}
+		update := request.Update()
+		update.Seasons = kept
+		if err := o.Client.UpdateRequest(ctx, request.ID, update); rest.IsNotFound(err) {
+			fmt.Printf(Yellow+"Request %d for '%v' was already removed from Overseer.\n"+Reset, request.ID, title)
+		} else if err != nil {
+			fmt.Println(Red + err.Error() + Reset)
+		} else {
+			o.Requests[i].Seasons = keptSeasons
+			plan.Annotate(fmt.Sprintf("Remove season(s) %s from Overseer request %d for '%s'", joinInts(dropped), request.ID, title), 0)
+			fmt.Printf(Green+"Removed season(s) %s from request %d for '%v'; it now covers season(s) %s.\n"+Reset, joinInts(dropped), request.ID, title, joinInts(kept))
+		}
+	}
+}
+
+// joinInts formats a list of numbers as a comma-separated string.
+func joinInts(numbers []int) string {
+	var parts []string
+	for _, n := range numbers {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return strings.Join(parts, ", ")
+}
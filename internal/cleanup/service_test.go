@@ -0,0 +1,91 @@
+package cleanup
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"slices"
+	"testing"
+)
+
+var regular = config.Instance{Name: "main"}
+
+// newOverseer returns Overseer data for media and requests whose client is answered from the
+// fixtures in testdata.
+func newOverseer(t *testing.T, strategy string, media []overseer.Media, requests []overseer.Request) (*Overseer, *rest.ReplayHandler) {
+	url, calls := rest.NewTestReplay(t, "testdata", "overseer")
+	client := overseer.NewOverseerClient(url, "", rest.Options{})
+	return &Overseer{Client: client, Media: media, Requests: requests, Strategy: strategy, removed: map[int]bool{}, removedRequests: map[int]bool{}}, calls
+}
+
+func TestRemove(t *testing.T) {
+	movie := overseer.Media{Id: 5, MediaType: "movie", TmdbId: 101, Status: overseer.MediaStatusAvailable, Status4k: overseer.MediaStatusUnknown}
+	on4K := movie
+	on4K.Status4k = overseer.MediaStatusAvailable
+	own := overseer.Request{ID: 701, Media: movie}
+	other := overseer.Request{ID: 702, Media: movie, Is4K: true}
+
+	tests := []struct {
+		name     string
+		strategy string
+		media    overseer.Media
+		requests []overseer.Request
+		want     []string
+	}{
+		{"unused media is deleted", DeleteMedia, movie, []overseer.Request{own}, []string{"DELETE /media/5"}},
+		{"media on the 4K server keeps it", DeleteMedia, on4K, []overseer.Request{own}, []string{"DELETE /request/701"}},
+		{"media requested for the 4K server keeps it", DeleteMedia, movie, []overseer.Request{own, other}, []string{"DELETE /request/701"}},
+		{"requests only", DeleteRequestsOnly, movie, []overseer.Request{own}, []string{"DELETE /request/701"}},
+		{"reset status", ResetStatus, movie, []overseer.Request{own}, []string{"POST /media/5/unknown"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, calls := newOverseer(t, tt.strategy, []overseer.Media{tt.media}, tt.requests)
+
+			o.Remove(context.Background(), regular, tt.media, "Big Old")
+
+			if got := calls.Served(); !slices.Equal(got, tt.want) {
+				t.Errorf("calls = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveSeasons(t *testing.T) {
+	show := overseer.Media{Id: 7, MediaType: "tv", TmdbId: 301, TvdbId: 201, Status: overseer.MediaStatusAvailable, Status4k: overseer.MediaStatusUnknown,
+		Seasons: []overseer.Season{
+			{SeasonNumber: 1, Status: overseer.MediaStatusAvailable, Status4k: overseer.MediaStatusUnknown},
+			{SeasonNumber: 2, Status: overseer.MediaStatusAvailable, Status4k: overseer.MediaStatusUnknown},
+		}}
+	request := overseer.Request{ID: 703, Media: show, RequestedBy: overseer.User{ID: 1}, ProfileID: 1, RootFolder: "/tv",
+		Seasons: []overseer.SeasonRequest{{SeasonNumber: 1}, {SeasonNumber: 2}}}
+
+	tests := []struct {
+		name        string
+		seasons     []int
+		want        []string
+		wantRemoved bool
+		wantStatus  int
+	}{
+		{"some seasons", []int{1}, []string{"PUT /request/703", "POST /media/7/partial"}, false, overseer.MediaStatusPartiallyAvailable},
+		{"every season", []int{1, 2}, []string{"DELETE /request/703", "POST /media/7/unknown"}, true, overseer.MediaStatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, calls := newOverseer(t, DeleteMedia, []overseer.Media{show}, []overseer.Request{request})
+
+			o.RemoveSeasons(context.Background(), regular, show, "Long Procedural", tt.seasons)
+
+			if got := calls.Served(); !slices.Equal(got, tt.want) {
+				t.Errorf("calls = %q, want %q", got, tt.want)
+			}
+			if got := o.Removed(request); got != tt.wantRemoved {
+				t.Errorf("Removed = %v, want %v", got, tt.wantRemoved)
+			}
+			if got := o.Media[0].Status; got != tt.wantStatus {
+				t.Errorf("media status = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+}
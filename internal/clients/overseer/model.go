@@ -2,7 +2,12 @@
 
 package overseer
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // apiResponse models the structure of the API's JSON response for media.
 type GetMediaResponse struct {
@@ -25,48 +30,198 @@ type pageInfo struct {
 
 // mediaItem represents each media entry returned by the API.
 type Media struct {
-	Id     int    `json:"id"`
-	TmdbId int    `json:"tmdbId"`
-	TvdbId int    `json:"tvdbId"`
-	Title  string `json:"title"`
-	Size   int64  `json:"size"`
-	// Other fields are omitted for brevity.
+	Id        int    `json:"id"`
+	MediaType string `json:"mediaType"`
+	TmdbId    int    `json:"tmdbId"`
+	TvdbId    int    `json:"tvdbId"`
+	ImdbId    string `json:"imdbId"`
+	Title     string `json:"title"`
+	Size      int64  `json:"size"`
+	// Status and Status4k are MediaStatus values for the regular and the 4K server.
+	Status   int `json:"status"`
+	Status4k int `json:"status4k"`
+	// ServiceId and ExternalServiceId are the Radarr/Sonarr server and the item's ID on it.
+	ServiceId           int       `json:"serviceId"`
+	ServiceId4k         int       `json:"serviceId4k"`
+	ExternalServiceId   int       `json:"externalServiceId"`
+	ExternalServiceId4k int       `json:"externalServiceId4k"`
+	Seasons             []Season  `json:"seasons"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+	MediaAddedAt        time.Time `json:"mediaAddedAt"`
+	LastSeasonChange    time.Time `json:"lastSeasonChange"`
 }
+
+// MediaStatus values used by Overseer.
+const (
+	MediaStatusUnknown            = 1
+	MediaStatusPending            = 2
+	MediaStatusProcessing         = 3
+	MediaStatusPartiallyAvailable = 4
+	MediaStatusAvailable          = 5
+)
+
+// MediaStatusName names a MediaStatus value.
+func MediaStatusName(status int) string {
+	switch status {
+	case MediaStatusUnknown:
+		return "unknown"
+	case MediaStatusPending:
+		return "pending"
+	case MediaStatusProcessing:
+		return "processing"
+	case MediaStatusPartiallyAvailable:
+		return "partially available"
+	case MediaStatusAvailable:
+		return "available"
+	}
+	return fmt.Sprint(status)
+}
+
+// Season is the availability of one season of a TV media item.
 type Season struct {
-	Id           int `json:"id"`
-	SeasonNumber int `json:"seasonNumber"`
+	Id           int       `json:"id"`
+	SeasonNumber int       `json:"seasonNumber"`
+	Status       int       `json:"status"`
+	Status4k     int       `json:"status4k"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// RequestStatus values used by Overseer.
+const (
+	RequestStatusPending   = 1
+	RequestStatusApproved  = 2
+	RequestStatusDeclined  = 3
+	RequestStatusFailed    = 4
+	RequestStatusCompleted = 5
+)
+
+// RequestStatusNames names the RequestStatus values, e.g. for filters and listings.
+var RequestStatusNames = map[int]string{
+	RequestStatusPending:   "pending",
+	RequestStatusApproved:  "approved",
+	RequestStatusDeclined:  "declined",
+	RequestStatusFailed:    "failed",
+	RequestStatusCompleted: "completed",
+}
+
+// RequestStatusName names a RequestStatus value.
+func RequestStatusName(status int) string {
+	if name, ok := RequestStatusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprint(status)
 }
 
 type Request struct {
-	ID          int         `json:"id"`
-	Status      int         `json:"status"`
-	Media       string      `json:"media"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt"`
-	RequestedBy RequestedBy `json:"requestedBy"`
-	ModifiedBy  ModifiedBy  `json:"modifiedBy"`
-	Is4K        bool        `json:"is4k"`
-	ServerID    int         `json:"serverId"`
-	ProfileID   int         `json:"profileId"`
-	RootFolder  string      `json:"rootFolder"`
-}
-type RequestedBy struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	Username     string    `json:"username"`
-	PlexToken    string    `json:"plexToken"`
-	PlexUsername string    `json:"plexUsername"`
-	UserType     int       `json:"userType"`
-	Permissions  int       `json:"permissions"`
-	Avatar       string    `json:"avatar"`
+	ID     int    `json:"id"`
+	Status int    `json:"status"`
+	Type   string `json:"type"`
+	Media  Media  `json:"media"`
+	// Seasons are the requested seasons of a TV request, each with its own RequestStatus.
+	Seasons           []SeasonRequest `json:"seasons"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+	RequestedBy       User            `json:"requestedBy"`
+	ModifiedBy        *User           `json:"modifiedBy"`
+	Is4K              bool            `json:"is4k"`
+	ServerID          int             `json:"serverId"`
+	ProfileID         int             `json:"profileId"`
+	RootFolder        string          `json:"rootFolder"`
+	LanguageProfileID int             `json:"languageProfileId"`
+	Tags              []int           `json:"tags"`
+	IsAutoRequest     bool            `json:"isAutoRequest"`
+}
+
+// RequestUpdate is the body of a request update. Unlike in Request, Seasons are season numbers.
+type RequestUpdate struct {
+	MediaType         string `json:"mediaType"`
+	Seasons           []int  `json:"seasons,omitempty"`
+	Is4K              bool   `json:"is4k"`
+	ServerID          int    `json:"serverId"`
+	ProfileID         int    `json:"profileId"`
+	RootFolder        string `json:"rootFolder,omitempty"`
+	LanguageProfileID int    `json:"languageProfileId,omitempty"`
+	UserID            int    `json:"userId"`
+	Tags              []int  `json:"tags,omitempty"`
+}
+
+// Update returns an update that leaves the request as it is.
+func (r Request) Update() RequestUpdate {
+	update := RequestUpdate{
+		MediaType:         r.Media.MediaType,
+		Is4K:              r.Is4K,
+		ServerID:          r.ServerID,
+		ProfileID:         r.ProfileID,
+		RootFolder:        r.RootFolder,
+		LanguageProfileID: r.LanguageProfileID,
+		UserID:            r.RequestedBy.ID,
+		Tags:              r.Tags,
+	}
+	for _, season := range r.Seasons {
+		update.Seasons = append(update.Seasons, season.SeasonNumber)
+	}
+	return update
+}
+
+// NewRequest is the body for creating a request. Zero server, profile and root folder values
+// leave the choice to Overseer's defaults, and a zero UserID attributes the request to the owner
+// of the API key.
+type NewRequest struct {
+	MediaType  string `json:"mediaType"`
+	MediaID    int    `json:"mediaId"` // TMDB ID
+	TvdbID     int    `json:"tvdbId,omitempty"`
+	Seasons    []int  `json:"seasons,omitempty"`
+	Is4K       bool   `json:"is4k"`
+	ServerID   *int   `json:"serverId,omitempty"`
+	ProfileID  int    `json:"profileId,omitempty"`
+	RootFolder string `json:"rootFolder,omitempty"`
+	UserID     int    `json:"userId,omitempty"`
+}
+
+// MovieDetails are the TMDB details Overseer has for a movie. Runtime is in minutes.
+type MovieDetails struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Runtime int    `json:"runtime"`
+}
+
+// TVDetails are the TMDB details Overseer has for a series. EpisodeRunTime is in minutes.
+type TVDetails struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	EpisodeRunTime []int      `json:"episodeRunTime"`
+	Seasons        []TVSeason `json:"seasons"`
+}
+
+// TVSeason is a season in TVDetails.
+type TVSeason struct {
+	SeasonNumber int `json:"seasonNumber"`
+	EpisodeCount int `json:"episodeCount"`
+}
+
+// getUsersResponse models the structure of the API's JSON response for users.
+type getUsersResponse struct {
+	PageInfo pageInfo `json:"pageInfo"`
+	Results  []User   `json:"results"`
+}
+
+// SeasonRequest is one requested season of a TV request.
+type SeasonRequest struct {
+	Id           int       `json:"id"`
+	SeasonNumber int       `json:"seasonNumber"`
+	Status       int       `json:"status"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
-	RequestCount int       `json:"requestCount"`
 }
-type ModifiedBy struct {
+
+// User is an Overseer user, e.g. the one who made a request.
+type User struct {
 	ID           int       `json:"id"`
 	Email        string    `json:"email"`
 	Username     string    `json:"username"`
+	DisplayName  string    `json:"displayName"`
 	PlexToken    string    `json:"plexToken"`
 	PlexUsername string    `json:"plexUsername"`
 	UserType     int       `json:"userType"`
@@ -76,3 +231,31 @@ type ModifiedBy struct {
 	UpdatedAt    time.Time `json:"updatedAt"`
 	RequestCount int       `json:"requestCount"`
 }
+
+// Matches reports whether a query names the user, by ID or by any of their names.
+func (u User) Matches(query string) bool {
+	if id, err := strconv.Atoi(query); err == nil {
+		return u.ID == id
+	}
+	for _, name := range []string{u.Username, u.DisplayName, u.PlexUsername, u.Email} {
+		if name != "" && strings.EqualFold(name, query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the most readable name Overseer has for a user.
+func (u User) Name() string {
+	for _, name := range []string{u.DisplayName, u.Username, u.PlexUsername, u.Email} {
+		if name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("user %d", u.ID)
+}
+
+// Status is the response of the status endpoint.
+type Status struct {
+	Version string `json:"version"`
+}
@@ -1,81 +1,51 @@
 package overseer
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/rest"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type OverseerClient struct {
 	BaseURL string
 	APIKey  string
-	Client  *http.Client
+	rest    *rest.Client
 }
 
-func NewOverseerClient(baseURL string, apiKey string) *OverseerClient {
+// NewOverseerClient creates a new OverseerClient with the given base URL, API key and transport options.
+func NewOverseerClient(baseURL string, apiKey string, opts rest.Options) *OverseerClient {
 	return &OverseerClient{
 		BaseURL: baseURL,
 		APIKey:  apiKey,
-		Client:  &http.Client{},
+		rest:    rest.NewClient("overseer", baseURL, map[string]string{"X-Api-Key": apiKey}, opts),
 	}
 }
 
-// setHeaders sets the required headers for an HTTP request.
-func (oc *OverseerClient) setHeaders(req *http.Request) {
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("X-Api-Key", oc.APIKey)
+// pageQuery builds the query string for one page of a paginated endpoint.
+func pageQuery(take, skip int, extra map[string]string) string {
+	query := url.Values{}
+	query.Set("take", strconv.Itoa(take))
+	query.Set("skip", strconv.Itoa(skip))
+	for key, value := range extra {
+		query.Set(key, value)
+	}
+	return query.Encode()
 }
 
 // GetMedia retrieves all media items from the API with pagination.
-func (oc *OverseerClient) GetMedia() ([]Media, error) {
+func (oc *OverseerClient) GetMedia(ctx context.Context) ([]Media, error) {
 	var allMedia []Media
 	take := 100
 	skip := 0
 
 	for {
-		endpoint, err := url.Parse(fmt.Sprintf("%s/media", oc.BaseURL))
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse base URL: %v", err)
-		}
-
-		query := endpoint.Query()
-		query.Set("take", strconv.Itoa(take))
-		query.Set("skip", strconv.Itoa(skip))
-		query.Set("sort", "added")
-		endpoint.RawQuery = query.Encode()
-
-		req, err := http.NewRequest("GET", endpoint.String(), nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %v", err)
-		}
-
-		oc.setHeaders(req)
-
-		resp, err := oc.Client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to make request: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			bodyString := string(bodyBytes)
-			return nil, fmt.Errorf("received non-OK HTTP status: %s, body: %s", resp.Status, bodyString)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %v", err)
-		}
-
 		var apiResp GetMediaResponse
-		err = json.Unmarshal(body, &apiResp)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		endpoint := "/media?" + pageQuery(take, skip, map[string]string{"sort": "added"})
+		if err := oc.rest.Get(ctx, endpoint, &apiResp); err != nil {
+			return nil, fmt.Errorf("failed to fetch media: %w", err)
 		}
 
 		allMedia = append(allMedia, apiResp.Results...)
@@ -90,50 +60,16 @@ func (oc *OverseerClient) GetMedia() ([]Media, error) {
 }
 
 // GetRequests retrieves all requests from the API with pagination.
-func (oc *OverseerClient) GetRequests() ([]Request, error) {
+func (oc *OverseerClient) GetRequests(ctx context.Context) ([]Request, error) {
 	var allRequests []Request
 	take := 100
 	skip := 0
 
 	for {
-		endpoint, err := url.Parse(fmt.Sprintf("%s/request", oc.BaseURL))
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse base URL: %v", err)
-		}
-
-		query := endpoint.Query()
-		query.Set("take", strconv.Itoa(take))
-		query.Set("skip", strconv.Itoa(skip))
-		endpoint.RawQuery = query.Encode()
-
-		req, err := http.NewRequest("GET", endpoint.String(), nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %v", err)
-		}
-
-		oc.setHeaders(req)
-
-		resp, err := oc.Client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to make request: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			bodyString := string(bodyBytes)
-			return nil, fmt.Errorf("received non-OK HTTP status: %s, body: %s", resp.Status, bodyString)
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %v", err)
-		}
-
 		var apiResp getRequestsResponse
-		err = json.Unmarshal(body, &apiResp)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		endpoint := "/request?" + pageQuery(take, skip, nil)
+		if err := oc.rest.Get(ctx, endpoint, &apiResp); err != nil {
+			return nil, fmt.Errorf("failed to fetch requests: %w", err)
 		}
 
 		allRequests = append(allRequests, apiResp.Results...)
@@ -147,82 +83,144 @@ func (oc *OverseerClient) GetRequests() ([]Request, error) {
 	return allRequests, nil
 }
 
-// DeleteMedia sends a DELETE request to the API to remove a media item by its ID.
-func (oc *OverseerClient) DeleteMedia(mediaId int) error {
-	endpoint := fmt.Sprintf("%s/media/%d", oc.BaseURL, mediaId)
+// GetUsers retrieves all users from the API with pagination.
+func (oc *OverseerClient) GetUsers(ctx context.Context) ([]User, error) {
+	var allUsers []User
+	take := 100
+	skip := 0
 
-	req, err := http.NewRequest("DELETE", endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+	for {
+		var apiResp getUsersResponse
+		endpoint := "/user?" + pageQuery(take, skip, nil)
+		if err := oc.rest.Get(ctx, endpoint, &apiResp); err != nil {
+			return nil, fmt.Errorf("failed to fetch users: %w", err)
+		}
+
+		allUsers = append(allUsers, apiResp.Results...)
+		skip += take
+		if skip >= apiResp.PageInfo.Results {
+			break
+		}
 	}
 
-	oc.setHeaders(req)
-	req.Header.Set("accept", "*/*") // Optional: override specific headers
+	return allUsers, nil
+}
 
-	resp, err := oc.Client.Do(req)
+// FindUser returns the single user a query names, by ID or by any of their names.
+func (oc *OverseerClient) FindUser(ctx context.Context, query string) (User, error) {
+	users, err := oc.GetUsers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+		return User{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("received non-OK HTTP status: %s", resp.Status)
+	var found []User
+	for _, user := range users {
+		if user.Matches(query) {
+			found = append(found, user)
+		}
 	}
-
-	return nil
+	switch len(found) {
+	case 0:
+		return User{}, fmt.Errorf("no Overseer user %q", query)
+	case 1:
+		return found[0], nil
+	}
+	var names []string
+	for _, user := range found {
+		names = append(names, fmt.Sprintf("%s (id %d)", user.Name(), user.ID))
+	}
+	return User{}, fmt.Errorf("%q matches several Overseer users: %s; use the user ID instead", query, strings.Join(names, ", "))
 }
 
-// UpdateRequest sends a PUT request to the API to update a request item.
-func (oc *OverseerClient) UpdateRequest(requestID int, updatedRequest Request) error {
-	requestBody, err := json.Marshal(updatedRequest)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request item: %v", err)
+// CreateRequest creates a request, which Overseer approves and sends on according to the user's permissions.
+func (oc *OverseerClient) CreateRequest(ctx context.Context, request NewRequest) (Request, error) {
+	var created Request
+	if err := oc.rest.Post(ctx, "/request", request, &created); err != nil {
+		return Request{}, fmt.Errorf("failed to create request for %s %d: %w", request.MediaType, request.MediaID, err)
 	}
+	return created, nil
+}
 
-	endpoint := fmt.Sprintf("%s/requests/%d", oc.BaseURL, requestID)
-
-	req, err := http.NewRequest("PUT", endpoint, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+// GetMovieDetails retrieves the details of a movie by its TMDB ID.
+func (oc *OverseerClient) GetMovieDetails(ctx context.Context, tmdbID int) (MovieDetails, error) {
+	var details MovieDetails
+	if err := oc.rest.Get(ctx, fmt.Sprintf("/movie/%d", tmdbID), &details); err != nil {
+		return MovieDetails{}, fmt.Errorf("failed to fetch movie %d: %w", tmdbID, err)
 	}
+	return details, nil
+}
 
-	oc.setHeaders(req)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := oc.Client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+// GetTVDetails retrieves the details of a series by its TMDB ID.
+func (oc *OverseerClient) GetTVDetails(ctx context.Context, tmdbID int) (TVDetails, error) {
+	var details TVDetails
+	if err := oc.rest.Get(ctx, fmt.Sprintf("/tv/%d", tmdbID), &details); err != nil {
+		return TVDetails{}, fmt.Errorf("failed to fetch series %d: %w", tmdbID, err)
 	}
-	defer resp.Body.Close()
+	return details, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received non-OK HTTP status: %s", resp.Status)
+// DeleteMedia sends a DELETE request to the API to remove a media item by its ID.
+func (oc *OverseerClient) DeleteMedia(ctx context.Context, mediaId int) error {
+	if err := oc.rest.Delete(ctx, fmt.Sprintf("/media/%d", mediaId), nil); err != nil {
+		return fmt.Errorf("failed to delete media %d: %w", mediaId, err)
 	}
-
 	return nil
 }
 
-// DeleteRequest sends a DELETE request to the API to remove a request by its ID.
-func (oc *OverseerClient) DeleteRequest(requestID int) error {
-	endpoint := fmt.Sprintf("%s/requests/%d", oc.BaseURL, requestID)
+// SetMediaStatus sets the availability of a media item on the regular or the 4K server.
+// status is one of "available", "partial", "processing", "pending" or "unknown".
+func (oc *OverseerClient) SetMediaStatus(ctx context.Context, mediaId int, status string, is4k bool) error {
+	body := map[string]bool{"is4k": is4k}
+	if err := oc.rest.Post(ctx, fmt.Sprintf("/media/%d/%s", mediaId, status), body, nil); err != nil {
+		return fmt.Errorf("failed to mark media %d as %s: %w", mediaId, status, err)
+	}
+	return nil
+}
 
-	req, err := http.NewRequest("DELETE", endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+// UpdateRequest sends a PUT request to the API to update a request item.
+func (oc *OverseerClient) UpdateRequest(ctx context.Context, requestID int, updatedRequest RequestUpdate) error {
+	if err := oc.rest.Put(ctx, fmt.Sprintf("/request/%d", requestID), updatedRequest, nil); err != nil {
+		return fmt.Errorf("failed to update request %d: %w", requestID, err)
 	}
+	return nil
+}
 
-	oc.setHeaders(req)
-	req.Header.Set("accept", "*/*") // Optional: override specific headers
+// ApproveRequest approves a pending request, which sends it to Radarr or Sonarr.
+func (oc *OverseerClient) ApproveRequest(ctx context.Context, requestID int) error {
+	if err := oc.rest.Post(ctx, fmt.Sprintf("/request/%d/approve", requestID), nil, nil); err != nil {
+		return fmt.Errorf("failed to approve request %d: %w", requestID, err)
+	}
+	return nil
+}
 
-	resp, err := oc.Client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+// DeclineRequest declines a pending request.
+func (oc *OverseerClient) DeclineRequest(ctx context.Context, requestID int) error {
+	if err := oc.rest.Post(ctx, fmt.Sprintf("/request/%d/decline", requestID), nil, nil); err != nil {
+		return fmt.Errorf("failed to decline request %d: %w", requestID, err)
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("received non-OK HTTP status: %s", resp.Status)
+// RetryRequest sends a failed request to Radarr or Sonarr again.
+func (oc *OverseerClient) RetryRequest(ctx context.Context, requestID int) error {
+	if err := oc.rest.Post(ctx, fmt.Sprintf("/request/%d/retry", requestID), nil, nil); err != nil {
+		return fmt.Errorf("failed to retry request %d: %w", requestID, err)
 	}
+	return nil
+}
 
+// DeleteRequest sends a DELETE request to the API to remove a request by its ID.
+func (oc *OverseerClient) DeleteRequest(ctx context.Context, requestID int) error {
+	if err := oc.rest.Delete(ctx, fmt.Sprintf("/request/%d", requestID), nil); err != nil {
+		return fmt.Errorf("failed to delete request %d: %w", requestID, err)
+	}
 	return nil
 }
+
+// GetStatus retrieves the version information of Overseer. It is a cheap way to check the url and API key.
+func (oc *OverseerClient) GetStatus(ctx context.Context) (Status, error) {
+	var status Status
+	if err := oc.rest.Get(ctx, "/status", &status); err != nil {
+		return Status{}, fmt.Errorf("failed to fetch status: %w", err)
+	}
+	return status, nil
+}
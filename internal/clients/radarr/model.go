@@ -205,3 +205,87 @@ type Statistics struct {
 	SizeOnDisk     int      `json:"sizeOnDisk"`
 	ReleaseGroups  []string `json:"releaseGroups"`
 }
+
+// NewMovie is the body for adding a movie, usually filled from a lookup result.
+type NewMovie struct {
+	Title               string     `json:"title"`
+	Year                int        `json:"year"`
+	TMDBID              int        `json:"tmdbId"`
+	QualityProfileID    int        `json:"qualityProfileId"`
+	RootFolderPath      string     `json:"rootFolderPath"`
+	Monitored           bool       `json:"monitored"`
+	MinimumAvailability string     `json:"minimumAvailability"`
+	Tags                []int      `json:"tags"`
+	AddOptions          AddOptions `json:"addOptions"`
+}
+
+type QualityProfile struct {
+	ID    int                  `json:"id"`
+	Name  string               `json:"name"`
+	Items []QualityProfileItem `json:"items"`
+}
+
+// AllowedQualities returns the IDs of the qualities the profile allows. Qualities in a group are
+// allowed together with the group.
+func (p QualityProfile) AllowedQualities() []int {
+	var allowed []int
+	for _, item := range p.Items {
+		if !item.Allowed {
+			continue
+		}
+		if item.Quality != nil {
+			allowed = append(allowed, item.Quality.ID)
+		}
+		for _, sub := range item.Items {
+			if sub.Quality != nil {
+				allowed = append(allowed, sub.Quality.ID)
+			}
+		}
+	}
+	return allowed
+}
+
+// QualityProfileItem is a quality of a profile, or a group of qualities when Items is set.
+type QualityProfileItem struct {
+	ID      int                  `json:"id"`
+	Name    string               `json:"name"`
+	Quality *QualityDetail       `json:"quality"`
+	Items   []QualityProfileItem `json:"items"`
+	Allowed bool                 `json:"allowed"`
+}
+
+// QualityDefinition holds the size limits of a quality in megabytes per minute of runtime.
+// A MaxSize of zero means no limit.
+type QualityDefinition struct {
+	ID            int           `json:"id"`
+	Quality       QualityDetail `json:"quality"`
+	Title         string        `json:"title"`
+	MinSize       float64       `json:"minSize"`
+	MaxSize       float64       `json:"maxSize"`
+	PreferredSize float64       `json:"preferredSize"`
+}
+
+// DiskSpace is the free space of a disk Radarr can see.
+type DiskSpace struct {
+	Path       string `json:"path"`
+	Label      string `json:"label"`
+	FreeSpace  int64  `json:"freeSpace"`
+	TotalSpace int64  `json:"totalSpace"`
+}
+
+type RootFolder struct {
+	ID         int    `json:"id"`
+	Path       string `json:"path"`
+	Accessible bool   `json:"accessible"`
+	FreeSpace  int64  `json:"freeSpace"`
+}
+
+type Tag struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+}
+
+// Status is the response of the status endpoint.
+type Status struct {
+	Version string `json:"version"`
+}
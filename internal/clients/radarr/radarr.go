@@ -1,67 +1,115 @@
 package radarr
 
 import (
-	"encoding/json"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/rest"
 	"fmt"
-	"net/http"
+	"net/url"
 )
 
 // RadarrClient holds the base URL and API key for the Radarr API.
 type RadarrClient struct {
 	BaseURL string
 	APIKey  string
+	rest    *rest.Client
 }
 
-// NewRadarrClient creates a new instance of RadarrClient with the given base URL and API key.
-func NewRadarrClient(baseURL, apiKey string) *RadarrClient {
+// NewRadarrClient creates a new instance of RadarrClient with the given base URL, API key and transport options.
+func NewRadarrClient(baseURL, apiKey string, opts rest.Options) *RadarrClient {
 	return &RadarrClient{
 		BaseURL: baseURL,
 		APIKey:  apiKey,
+		rest:    rest.NewClient("radarr", baseURL, map[string]string{"X-Api-Key": apiKey}, opts),
 	}
 }
 
 // FetchMovies retrieves the list of movies from Radarr.
-func (client *RadarrClient) GetMovies() ([]Movie, error) {
-	params := "/movie?excludeLocalCovers=false&apikey=" + client.APIKey
-	resp, err := http.Get(client.BaseURL + params)
-	if err != nil {
+func (client *RadarrClient) GetMovies(ctx context.Context) ([]Movie, error) {
+	var movies []Movie
+	if err := client.rest.Get(ctx, "/movie?excludeLocalCovers=false", &movies); err != nil {
 		return nil, fmt.Errorf("error fetching movies: %w", err)
 	}
-	defer resp.Body.Close()
+	return movies, nil
+}
 
-	var movies []Movie
-	if err := json.NewDecoder(resp.Body).Decode(&movies); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+// DeleteMovie sends a DELETE request to the Radarr API to remove a movie by its ID.
+func (client *RadarrClient) DeleteMovie(ctx context.Context, movieID int) error {
+	endpoint := fmt.Sprintf("/movie/%d?deleteFiles=true", movieID)
+	if err := client.rest.Delete(ctx, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to delete movie with ID %d: %w", movieID, err)
 	}
+	return nil
+}
 
+// LookupMovie searches Radarr's metadata for movies. term is a title or "tmdb:<id>" / "imdb:<id>".
+// Movies already in the library have an ID.
+func (client *RadarrClient) LookupMovie(ctx context.Context, term string) ([]Movie, error) {
+	var movies []Movie
+	if err := client.rest.Get(ctx, "/movie/lookup?term="+url.QueryEscape(term), &movies); err != nil {
+		return nil, fmt.Errorf("error looking up movie %q: %w", term, err)
+	}
 	return movies, nil
 }
 
-// DeleteMovie sends a DELETE request to the Radarr API to remove a movie by its ID.
-func (client *RadarrClient) DeleteMovie(movieID int) error {
-	// Construct the API endpoint for the DELETE request.
-	endpoint := fmt.Sprintf("%s/movie/%d?deleteFiles=true&apikey=%s", client.BaseURL, movieID, client.APIKey)
+// AddMovie adds a movie to Radarr.
+func (client *RadarrClient) AddMovie(ctx context.Context, movie NewMovie) (Movie, error) {
+	var added Movie
+	if err := client.rest.Post(ctx, "/movie", movie, &added); err != nil {
+		return Movie{}, fmt.Errorf("failed to add movie '%s': %w", movie.Title, err)
+	}
+	return added, nil
+}
 
-	// Create the HTTP DELETE request.
-	req, err := http.NewRequest("DELETE", endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// GetQualityProfiles retrieves the quality profiles defined in Radarr.
+func (client *RadarrClient) GetQualityProfiles(ctx context.Context) ([]QualityProfile, error) {
+	var profiles []QualityProfile
+	if err := client.rest.Get(ctx, "/qualityprofile", &profiles); err != nil {
+		return nil, fmt.Errorf("error fetching quality profiles: %w", err)
 	}
+	return profiles, nil
+}
 
-	// Set required headers.
-	req.Header.Set("accept", "*/*")
+// GetRootFolders retrieves the root folders defined in Radarr.
+func (client *RadarrClient) GetRootFolders(ctx context.Context) ([]RootFolder, error) {
+	var folders []RootFolder
+	if err := client.rest.Get(ctx, "/rootfolder", &folders); err != nil {
+		return nil, fmt.Errorf("error fetching root folders: %w", err)
+	}
+	return folders, nil
+}
 
-	// Execute the HTTP request.
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete movie: %w", err)
+// GetQualityDefinitions retrieves the size limits of every quality.
+func (client *RadarrClient) GetQualityDefinitions(ctx context.Context) ([]QualityDefinition, error) {
+	var definitions []QualityDefinition
+	if err := client.rest.Get(ctx, "/qualitydefinition", &definitions); err != nil {
+		return nil, fmt.Errorf("error fetching quality definitions: %w", err)
 	}
-	defer resp.Body.Close()
+	return definitions, nil
+}
 
-	// Handle non-OK HTTP responses.
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete movie with ID %d. Status code: %d", movieID, resp.StatusCode)
+// GetDiskSpace retrieves the free space of the disks Radarr can see.
+func (client *RadarrClient) GetDiskSpace(ctx context.Context) ([]DiskSpace, error) {
+	var disks []DiskSpace
+	if err := client.rest.Get(ctx, "/diskspace", &disks); err != nil {
+		return nil, fmt.Errorf("error fetching disk space: %w", err)
 	}
+	return disks, nil
+}
 
-	return nil
+// GetTags retrieves the list of tags defined in Radarr.
+func (client *RadarrClient) GetTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if err := client.rest.Get(ctx, "/tag", &tags); err != nil {
+		return nil, fmt.Errorf("error fetching tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetStatus retrieves the version information of Radarr. It is a cheap way to check the url and API key.
+func (client *RadarrClient) GetStatus(ctx context.Context) (Status, error) {
+	var status Status
+	if err := client.rest.Get(ctx, "/system/status", &status); err != nil {
+		return Status{}, fmt.Errorf("failed to fetch status: %w", err)
+	}
+	return status, nil
 }
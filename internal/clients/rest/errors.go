@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIError is returned by every client method when a service answers with a non-2xx status.
+type APIError struct {
+	Service    string
+	Method     string
+	Endpoint   string // with secrets redacted
+	StatusCode int
+	Message    string // parsed from the error body when possible
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s %s returned %d %s", e.Service, e.Method, e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// newAPIError builds an APIError and extracts a readable message from the response body.
+func newAPIError(service, method, endpoint string, status int, body []byte) *APIError {
+	return &APIError{
+		Service:    service,
+		Method:     method,
+		Endpoint:   Redact(endpoint),
+		StatusCode: status,
+		Message:    parseErrorMessage(body),
+		Body:       string(body),
+	}
+}
+
+// parseErrorMessage understands the error bodies of the *arr applications
+// ({"message": "...", "description": "..."} or a list of validation failures)
+// and Overseerr ({"message": "...", "errors": [...]}).
+func parseErrorMessage(body []byte) string {
+	var single struct {
+		Message     string `json:"message"`
+		Description string `json:"description"`
+		Errors      []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &single); err == nil && single.Message != "" {
+		parts := []string{single.Message}
+		if single.Description != "" {
+			parts = append(parts, single.Description)
+		}
+		for _, e := range single.Errors {
+			if e.Message != "" {
+				parts = append(parts, e.Message)
+			}
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	var validation []struct {
+		PropertyName string `json:"propertyName"`
+		ErrorMessage string `json:"errorMessage"`
+	}
+	if err := json.Unmarshal(body, &validation); err == nil && len(validation) > 0 {
+		var parts []string
+		for _, v := range validation {
+			parts = append(parts, fmt.Sprintf("%s: %s", v.PropertyName, v.ErrorMessage))
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	text := strings.TrimSpace(string(body))
+	if len(text) > 200 {
+		text = text[:200] + "..."
+	}
+	return text
+}
+
+// Redact removes API keys and other secrets from a URL's query string.
+func Redact(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	query := u.Query()
+	for key := range query {
+		switch strings.ToLower(key) {
+		case "apikey", "api_key", "token", "x-api-key":
+			query.Set(key, "REDACTED")
+		}
+	}
+	u.RawQuery = query.Encode()
+	if u.User != nil {
+		u.User = url.User(u.User.Username())
+	}
+	return u.String()
+}
+
+// StatusCode returns the HTTP status of an APIError, or 0 for any other error.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an APIError with status 401 or 403.
+func IsUnauthorized(err error) bool {
+	status := StatusCode(err)
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
+
+// IsServerError reports whether err is an APIError with a 5xx status.
+func IsServerError(err error) bool {
+	return StatusCode(err) >= 500
+}
+
+// IsUnreachable reports whether err means the service could not be reached at all.
+func IsUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,244 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Exchange is one recorded HTTP request and its response. Fixtures are stored as one
+// Exchange per JSON file so they can be read and edited by hand.
+type Exchange struct {
+	Service      string          `json:"service"`
+	Method       string          `json:"method"`
+	Path         string          `json:"path"` // relative to the service base URL, secrets redacted
+	RequestBody  json.RawMessage `json:"requestBody,omitempty"`
+	Status       int             `json:"status"`
+	ResponseBody json.RawMessage `json:"responseBody,omitempty"`
+}
+
+// sequence numbers fixture files so they sort in the order they were recorded. It
+// continues after the fixtures already in the directory so repeated runs don't collide.
+var (
+	sequence     int64
+	sequenceOnce sync.Once
+)
+
+// RecordingTransport sends requests through Next and saves every exchange below Dir/Service.
+// API keys are removed from the saved paths, headers and bodies.
+type RecordingTransport struct {
+	Next    http.RoundTripper
+	Dir     string
+	Service string
+	BaseURL string
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	secret := req.Header.Get("X-Api-Key")
+	exchange := Exchange{
+		Service:      t.Service,
+		Method:       req.Method,
+		Path:         relativePath(t.BaseURL, Redact(req.URL.String())),
+		RequestBody:  rawJSON(scrub(reqBody, secret)),
+		Status:       resp.StatusCode,
+		ResponseBody: rawJSON(scrub(respBody, secret)),
+	}
+	if err := t.save(exchange); err != nil {
+		return nil, fmt.Errorf("failed to record %s %s: %w", req.Method, exchange.Path, err)
+	}
+	return resp, nil
+}
+
+// save writes an exchange to its own numbered file.
+func (t *RecordingTransport) save(exchange Exchange) error {
+	dir := filepath.Join(t.Dir, t.Service)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+	sequenceOnce.Do(func() {
+		existing, _ := filepath.Glob(filepath.Join(t.Dir, "*", "*.json"))
+		atomic.StoreInt64(&sequence, int64(len(existing)))
+	})
+	name := fmt.Sprintf("%04d-%s-%s.json", atomic.AddInt64(&sequence, 1), exchange.Method, slug(exchange.Path))
+	return os.WriteFile(filepath.Join(dir, name), data, 0o644)
+}
+
+// ReplayTransport answers requests from fixtures recorded by RecordingTransport
+// without touching the network.
+type ReplayTransport struct {
+	handler *ReplayHandler
+}
+
+// NewReplayTransport loads the fixtures of a service from dir.
+func NewReplayTransport(dir, service, baseURL string) (*ReplayTransport, error) {
+	handler, err := NewReplayHandler(dir, service, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayTransport{handler: handler}, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	resp := recorder.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// ReplayHandler serves recorded exchanges. It can be mounted on an httptest.Server so
+// service code can be exercised end to end against fixtures. Requests are matched by
+// method and path; when the same request was recorded several times the responses are
+// served in order and the last one is repeated.
+type ReplayHandler struct {
+	baseURL   string
+	mu        sync.Mutex
+	exchanges map[string][]Exchange
+	served    []string
+}
+
+// NewReplayHandler loads every fixture below dir/service. baseURL is stripped from incoming
+// request URLs before matching; pass "" when requests arrive with paths relative to the fixtures.
+func NewReplayHandler(dir, service, baseURL string) (*ReplayHandler, error) {
+	files, err := filepath.Glob(filepath.Join(dir, service, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	h := &ReplayHandler{baseURL: baseURL, exchanges: map[string][]Exchange{}}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", file, err)
+		}
+		key := exchange.Method + " " + exchange.Path
+		h.exchanges[key] = append(h.exchanges[key], exchange)
+	}
+	return h, nil
+}
+
+func (h *ReplayHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := relativePath(h.baseURL, Redact(req.URL.String()))
+	if h.baseURL == "" {
+		path = Redact(req.URL.RequestURI())
+	}
+	key := req.Method + " " + path
+
+	h.mu.Lock()
+	h.served = append(h.served, key)
+	queue := h.exchanges[key]
+	var exchange *Exchange
+	if len(queue) > 0 {
+		exchange = &queue[0]
+		if len(queue) > 1 {
+			h.exchanges[key] = queue[1:]
+		}
+	}
+	h.mu.Unlock()
+
+	if exchange == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotImplemented)
+		fmt.Fprintf(w, `{"message": %q}`, "no recorded response for "+key)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(exchange.Status)
+	w.Write(replayBody(exchange.ResponseBody))
+}
+
+// Served returns every request the handler received as "METHOD path", in order, including
+// those it had no recorded response for.
+func (h *ReplayHandler) Served() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.served...)
+}
+
+// relativePath strips the service base URL from a full URL.
+func relativePath(baseURL, full string) string {
+	if baseURL != "" && strings.HasPrefix(full, baseURL) {
+		return strings.TrimPrefix(full, baseURL)
+	}
+	return full
+}
+
+// scrub replaces every occurrence of secret in body.
+func scrub(body []byte, secret string) []byte {
+	if secret == "" {
+		return body
+	}
+	return bytes.ReplaceAll(body, []byte(secret), []byte("REDACTED"))
+}
+
+// rawJSON keeps JSON bodies readable in fixtures and stores anything else as a JSON string.
+func rawJSON(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// replayBody undoes rawJSON for bodies that weren't JSON to begin with.
+func replayBody(body json.RawMessage) []byte {
+	var text string
+	if len(body) > 0 && body[0] == '"' && json.Unmarshal(body, &text) == nil {
+		return []byte(text)
+	}
+	return body
+}
+
+var slugPattern = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// slug turns a request path into something usable in a file name.
+func slug(path string) string {
+	s := strings.Trim(slugPattern.ReplaceAllString(path, "-"), "-")
+	if len(s) > 60 {
+		s = s[:60]
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "title": "Big Old", "apiKey": "secret"}`))
+	}))
+	defer upstream.Close()
+	dir := t.TempDir()
+	headers := map[string]string{"X-Api-Key": "secret"}
+
+	var recorded struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	recorder := NewClient("radarr", upstream.URL+"/api/v3", headers, Options{RecordDir: dir})
+	if err := recorder.Get(ctx, "/movie/1?apikey=secret", &recorded); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "radarr", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("recorded %d fixtures, want 1", len(files))
+	}
+	fixture, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(fixture), "secret") {
+		t.Errorf("fixture contains the API key:\n%s", fixture)
+	}
+	if !strings.Contains(string(fixture), `"path": "/movie/1?apikey=REDACTED"`) {
+		t.Errorf("fixture path is not relative and redacted:\n%s", fixture)
+	}
+
+	// The replaying client never reaches its base URL.
+	replayer := NewClient("radarr", "http://radarr.invalid/api/v3", headers, Options{ReplayDir: dir})
+	var replayed struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	if err := replayer.Get(ctx, "/movie/1?apikey=secret", &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if replayed != recorded {
+		t.Errorf("replayed %+v, want %+v", replayed, recorded)
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	replayer := NewClient("radarr", "http://radarr.invalid/api/v3", nil, Options{ReplayDir: t.TempDir()})
+	err := replayer.Get(context.Background(), "/movie", nil)
+	if err == nil {
+		t.Fatal("expected an error for a call without a fixture")
+	}
+	if StatusCode(err) != http.StatusNotImplemented || !strings.Contains(err.Error(), "no recorded response for GET /movie") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
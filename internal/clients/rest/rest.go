@@ -3,110 +3,279 @@ package rest
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
 	"time"
+
+	"flashbacklabsio/fcli/internal/plan"
+)
+
+// Default transport settings used when a service doesn't configure its own.
+const (
+	DefaultTimeout   = 30 * time.Second
+	DefaultRetries   = 3
+	DefaultRetryWait = time.Second
 )
 
+// Options configures the HTTP transport of a service client.
+type Options struct {
+	Timeout            time.Duration
+	Retries            int
+	RetryWait          time.Duration
+	CAFile             string
+	InsecureSkipVerify bool
+	Proxy              string
+
+	// RecordDir saves every exchange as a fixture below this directory.
+	RecordDir string
+	// ReplayDir answers requests from fixtures below this directory instead of the network.
+	ReplayDir string
+}
+
+// DefaultOptions returns the transport settings used when nothing is configured.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:   DefaultTimeout,
+		Retries:   DefaultRetries,
+		RetryWait: DefaultRetryWait,
+	}
+}
+
+// Client is the shared HTTP transport used by the Radarr, Sonarr and Overseer clients.
 type Client struct {
+	Service    string
 	BaseURL    string
+	Headers    map[string]string
 	HTTPClient *http.Client
+	Retries    int
+	RetryWait  time.Duration
+
+	// configErr is returned from every request when the transport could not be configured.
+	configErr error
 }
 
-func NewClient(baseURL string) *Client {
+// NewClient creates a client for the named service. Requests are sent relative to baseURL
+// and always carry the given headers.
+func NewClient(service, baseURL string, headers map[string]string, opts Options) *Client {
+	var transport http.RoundTripper
+	transport, err := newTransport(opts)
+	if err == nil && opts.ReplayDir != "" {
+		transport, err = NewReplayTransport(opts.ReplayDir, service, baseURL)
+		opts.Retries = 0
+	} else if opts.RecordDir != "" {
+		transport = &RecordingTransport{Next: transport, Dir: opts.RecordDir, Service: service, BaseURL: baseURL}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.RetryWait <= 0 {
+		opts.RetryWait = DefaultRetryWait
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
 	return &Client{
+		Service: service,
 		BaseURL: baseURL,
+		Headers: headers,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   opts.Timeout,
+			Transport: transport,
 		},
+		Retries:   opts.Retries,
+		RetryWait: opts.RetryWait,
+		configErr: err,
 	}
 }
 
-func (c *Client) Get(endpoint string, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest("GET", c.BaseURL+endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
+// newTransport builds an http.Transport with the TLS and proxy settings from opts.
+func newTransport(opts Options) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return transport, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return transport, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		transport.TLSClientConfig.RootCAs = pool
 	}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return transport, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
 	}
-	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
+	return transport, nil
 }
 
-func (c *Client) Post(endpoint string, body interface{}, headers map[string]string) ([]byte, error) {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
+// Get sends a GET request and decodes the JSON response into out.
+func (c *Client) Get(ctx context.Context, endpoint string, out interface{}) error {
+	return c.Do(ctx, "GET", endpoint, nil, out)
+}
+
+// Post sends body as JSON in a POST request and decodes the JSON response into out.
+func (c *Client) Post(ctx context.Context, endpoint string, body interface{}, out interface{}) error {
+	return c.Do(ctx, "POST", endpoint, body, out)
+}
+
+// Put sends body as JSON in a PUT request and decodes the JSON response into out.
+func (c *Client) Put(ctx context.Context, endpoint string, body interface{}, out interface{}) error {
+	return c.Do(ctx, "PUT", endpoint, body, out)
+}
+
+// Delete sends a DELETE request, with body as JSON when it isn't nil.
+func (c *Client) Delete(ctx context.Context, endpoint string, body interface{}) error {
+	return c.Do(ctx, "DELETE", endpoint, body, nil)
+}
+
+// Do sends a request and decodes the JSON response into out (when out isn't nil).
+// Any non-2xx response is returned as an *APIError.
+// Mutating requests are only recorded when dry-run mode is enabled. Connection errors,
+// 5xx and 429 responses are retried with exponential backoff.
+func (c *Client) Do(ctx context.Context, method, endpoint string, body interface{}, out interface{}) error {
+	if c.configErr != nil {
+		return fmt.Errorf("%s: %w", c.Service, c.configErr)
 	}
 
-	req, err := http.NewRequest("POST", c.BaseURL+endpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, err
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	if method != "GET" {
+		if p := plan.Active(); p != nil {
+			p.Record(c.Service, method, Redact(c.BaseURL+endpoint), payload)
+			return nil
+		}
 	}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+	var lastErr error
+	var wait time.Duration
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			if wait <= 0 {
+				wait = c.RetryWait * time.Duration(1<<(attempt-1))
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			wait = 0
+		}
+
+		resp, err := c.send(ctx, method, endpoint, payload)
+		if err != nil {
+			if ctx.Err() != nil || !retryable(method, 0) {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.status < 200 || resp.status > 299 {
+			lastErr = newAPIError(c.Service, method, c.BaseURL+endpoint, resp.status, resp.body)
+			wait = resp.retryAfter
+			if retryable(method, resp.status) {
+				continue
+			}
+			break
+		}
+
+		if out != nil && len(resp.body) > 0 {
+			if err := json.Unmarshal(resp.body, out); err != nil {
+				return fmt.Errorf("error decoding response: %w", err)
+			}
+		}
+		return nil
 	}
-	defer resp.Body.Close()
+	return lastErr
+}
 
-	return io.ReadAll(resp.Body)
+// response is the part of an HTTP response the clients care about.
+type response struct {
+	status     int
+	body       []byte
+	retryAfter time.Duration
 }
 
-func (c *Client) Put(endpoint string, body interface{}, headers map[string]string) ([]byte, error) {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
+// send performs a single HTTP round trip.
+func (c *Client) send(ctx context.Context, method, endpoint string, payload []byte) (*response, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
 	}
-
-	req, err := http.NewRequest("PUT", c.BaseURL+endpoint, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+endpoint, reader)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	for key, value := range headers {
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.Headers {
 		req.Header.Set(key, value)
 	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to reach %s: %w", c.Service, err)
 	}
 	defer resp.Body.Close()
 
-	return io.ReadAll(resp.Body)
-}
-
-func (c *Client) Delete(endpoint string, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest("DELETE", c.BaseURL+endpoint, nil)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	r := &response{status: resp.StatusCode, body: body}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		r.retryAfter = time.Duration(seconds) * time.Second
 	}
+	return r, nil
+}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
+// retryable reports whether a failed request may be sent again. Status 0 means the
+// request failed before a response was received. POST is only retried on 429 since
+// it isn't idempotent.
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
 	}
-	defer resp.Body.Close()
+	if method == "POST" {
+		return false
+	}
+	return status == 0 || status >= 500
+}
 
-	return io.ReadAll(resp.Body)
+// sleep waits for d or until the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
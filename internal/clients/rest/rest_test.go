@@ -0,0 +1,27 @@
+package rest
+
+import "testing"
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   bool
+	}{
+		{"GET", 0, true},
+		{"GET", 500, true},
+		{"GET", 404, false},
+		{"GET", 429, true},
+		{"DELETE", 503, true},
+		{"PUT", 400, false},
+		// A POST may have taken effect, so only rate limiting is retried.
+		{"POST", 0, false},
+		{"POST", 500, false},
+		{"POST", 429, true},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.method, tt.status); got != tt.want {
+			t.Errorf("retryable(%s, %d) = %v, want %v", tt.method, tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// NewTestReplay serves the fixtures of a service below dir on a local server that is closed when
+// the test ends. It returns the server's URL, to use as the base URL of a client or instance,
+// and the handler, whose Served lists the calls the test made.
+func NewTestReplay(t testing.TB, dir, service string) (string, *ReplayHandler) {
+	t.Helper()
+	handler, err := NewReplayHandler(dir, service, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL, handler
+}
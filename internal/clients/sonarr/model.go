@@ -102,7 +102,7 @@ type EpisodeFile struct {
 	SceneName           string          `json:"sceneName"`
 	ReleaseGroup        string          `json:"releaseGroup"`
 	Languages           []Languages     `json:"languages"`
-	Quality             Quality         `json:"quality"`
+	Quality             QualityModel    `json:"quality"`
 	CustomFormats       []CustomFormats `json:"customFormats"`
 	CustomFormatScore   int             `json:"customFormatScore"`
 	IndexerFlags        int             `json:"indexerFlags"`
@@ -110,10 +110,27 @@ type EpisodeFile struct {
 	MediaInfo           MediaInfo       `json:"mediaInfo"`
 	QualityCutoffNotMet bool            `json:"qualityCutoffNotMet"`
 }
+type Episode struct {
+	ID            int       `json:"id"`
+	SeriesID      int       `json:"seriesId"`
+	EpisodeFileID int       `json:"episodeFileId"`
+	SeasonNumber  int       `json:"seasonNumber"`
+	EpisodeNumber int       `json:"episodeNumber"`
+	Title         string    `json:"title"`
+	AirDate       string    `json:"airDate"`
+	AirDateUtc    time.Time `json:"airDateUtc"`
+	Overview      string    `json:"overview"`
+	HasFile       bool      `json:"hasFile"`
+	Monitored     bool      `json:"monitored"`
+}
 type Languages struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
+type QualityModel struct {
+	Quality  Quality  `json:"quality"`
+	Revision Revision `json:"revision"`
+}
 type Quality struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
@@ -185,3 +202,87 @@ type MediaInfo struct {
 	ScanType              string  `json:"scanType"`
 	Subtitles             string  `json:"subtitles"`
 }
+
+// NewSeries is the body for adding a series, usually filled from a lookup result.
+type NewSeries struct {
+	Title            string     `json:"title"`
+	Year             int        `json:"year"`
+	TvdbID           int        `json:"tvdbId"`
+	QualityProfileID int        `json:"qualityProfileId"`
+	RootFolderPath   string     `json:"rootFolderPath"`
+	SeriesType       string     `json:"seriesType"`
+	SeasonFolder     bool       `json:"seasonFolder"`
+	Monitored        bool       `json:"monitored"`
+	Seasons          []Season   `json:"seasons"`
+	Tags             []int      `json:"tags"`
+	AddOptions       AddOptions `json:"addOptions"`
+}
+type QualityProfile struct {
+	ID    int                  `json:"id"`
+	Name  string               `json:"name"`
+	Items []QualityProfileItem `json:"items"`
+}
+
+// AllowedQualities returns the IDs of the qualities the profile allows. Qualities in a group are
+// allowed together with the group.
+func (p QualityProfile) AllowedQualities() []int {
+	var allowed []int
+	for _, item := range p.Items {
+		if !item.Allowed {
+			continue
+		}
+		if item.Quality != nil {
+			allowed = append(allowed, item.Quality.ID)
+		}
+		for _, sub := range item.Items {
+			if sub.Quality != nil {
+				allowed = append(allowed, sub.Quality.ID)
+			}
+		}
+	}
+	return allowed
+}
+
+// QualityProfileItem is a quality of a profile, or a group of qualities when Items is set.
+type QualityProfileItem struct {
+	ID      int                  `json:"id"`
+	Name    string               `json:"name"`
+	Quality *Quality             `json:"quality"`
+	Items   []QualityProfileItem `json:"items"`
+	Allowed bool                 `json:"allowed"`
+}
+
+// QualityDefinition holds the size limits of a quality in megabytes per minute of runtime.
+// A MaxSize of zero means no limit.
+type QualityDefinition struct {
+	ID            int     `json:"id"`
+	Quality       Quality `json:"quality"`
+	Title         string  `json:"title"`
+	MinSize       float64 `json:"minSize"`
+	MaxSize       float64 `json:"maxSize"`
+	PreferredSize float64 `json:"preferredSize"`
+}
+
+// DiskSpace is the free space of a disk Sonarr can see.
+type DiskSpace struct {
+	Path       string `json:"path"`
+	Label      string `json:"label"`
+	FreeSpace  int64  `json:"freeSpace"`
+	TotalSpace int64  `json:"totalSpace"`
+}
+type RootFolder struct {
+	ID         int    `json:"id"`
+	Path       string `json:"path"`
+	Accessible bool   `json:"accessible"`
+	FreeSpace  int64  `json:"freeSpace"`
+}
+
+type Tag struct {
+	ID    int    `json:"id"`
+	Label string `json:"label"`
+}
+
+// Status is the response of the status endpoint.
+type Status struct {
+	Version string `json:"version"`
+}
@@ -1,77 +1,41 @@
 package sonarr
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/rest"
 	"fmt"
-	"net/http"
+	"net/url"
 )
 
 type SonarrClient struct {
 	baseURL string
 	apiKey  string
+	rest    *rest.Client
 }
 
-func NewSonarrClient(baseURL, apiKey string) *SonarrClient {
+// NewSonarrClient creates a new SonarrClient with the given base URL, API key and transport options.
+func NewSonarrClient(baseURL, apiKey string, opts rest.Options) *SonarrClient {
 	return &SonarrClient{
 		baseURL: baseURL,
 		apiKey:  apiKey,
+		rest:    rest.NewClient("sonarr", baseURL, map[string]string{"X-Api-Key": apiKey}, opts),
 	}
 }
 
-// setHeaders sets the common headers for a Sonarr API request.
-func (c *SonarrClient) setHeaders(req *http.Request) {
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Api-Key", c.apiKey)
-}
-
 // GetAllSeries fetches all series from the Sonarr API.
-func (c *SonarrClient) GetAllSeries() ([]Series, error) {
-	params := "/series"
-	req, err := http.NewRequest("GET", c.baseURL+params, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-	c.setHeaders(req)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching series: %v", err)
-	}
-	defer resp.Body.Close()
-
+func (c *SonarrClient) GetAllSeries(ctx context.Context) ([]Series, error) {
 	var series []Series
-	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
-		return nil, fmt.Errorf("error decoding response: %v", err)
+	if err := c.rest.Get(ctx, "/series", &series); err != nil {
+		return nil, fmt.Errorf("error fetching series: %w", err)
 	}
-
 	return series, nil
 }
 
 // GetEpisodeFiles fetches all episode files for a specific series from the Sonarr API.
-func (c *SonarrClient) GetEpiosdeFilesForSeries(seriesID int, seasonNumber *int) ([]EpisodeFile, error) {
-	params := fmt.Sprintf("/episodefile?seriesId=%d", seriesID)
-	req, err := http.NewRequest("GET", c.baseURL+params, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-	c.setHeaders(req)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch episode files: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch episode files. Status code: %d", resp.StatusCode)
-	}
-
+func (c *SonarrClient) GetEpiosdeFilesForSeries(ctx context.Context, seriesID int, seasonNumber *int) ([]EpisodeFile, error) {
 	var episodeFiles []EpisodeFile
-	if err := json.NewDecoder(resp.Body).Decode(&episodeFiles); err != nil {
-		return nil, fmt.Errorf("error decoding response: %v", err)
+	if err := c.rest.Get(ctx, fmt.Sprintf("/episodefile?seriesId=%d", seriesID), &episodeFiles); err != nil {
+		return nil, fmt.Errorf("failed to fetch episode files: %w", err)
 	}
 
 	// If seasonNumber is provided, filter the results.
@@ -89,75 +53,23 @@ func (c *SonarrClient) GetEpiosdeFilesForSeries(seriesID int, seasonNumber *int)
 }
 
 // UpdateSeries sends a PUT request to the Sonarr API to update a series by its ID.
-func (c *SonarrClient) UpdateSeries(series Series) error {
-	client := &http.Client{}
-
-	// Convert the Series struct to JSON.
-	requestBody, err := json.Marshal(series)
-	if err != nil {
-		return fmt.Errorf("failed to marshal series: %v", series)
+func (c *SonarrClient) UpdateSeries(ctx context.Context, series Series) error {
+	if err := c.rest.Put(ctx, fmt.Sprintf("/series/%d", series.ID), series, nil); err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
 	}
-	// Construct the API endpoint for the PUT request.
-	endpoint := fmt.Sprintf("%s/series/%d", c.baseURL, series.ID)
-
-	// Create the HTTP PUT request.
-	req, err := http.NewRequest("PUT", endpoint, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-	// Set required headers.
-	c.setHeaders(req)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Execute the HTTP request.
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to update series: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Handle non-OK HTTP responses.
-	if resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("received non-OK HTTP status: %s", resp.Status)
-	}
-
 	return nil
 }
 
 // DeleteSeries deletes a specific series from the Sonarr API.
-func (c *SonarrClient) DeleteSeries(seriesID int) error {
-	client := &http.Client{}
-
-	// Construct the API endpoint for the DELETE request.
-	endpoint := fmt.Sprintf("%s/series/%d?deleteFiles=true", c.baseURL, seriesID)
-
-	// Create the HTTP DELETE request.
-	req, err := http.NewRequest("DELETE", endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Set required headers.
-	c.setHeaders(req)
-	req.Header.Set("Accept", "*/*")
-
-	// Execute the HTTP request.
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete series: %v", err)
+func (c *SonarrClient) DeleteSeries(ctx context.Context, seriesID int) error {
+	if err := c.rest.Delete(ctx, fmt.Sprintf("/series/%d?deleteFiles=true", seriesID), nil); err != nil {
+		return fmt.Errorf("failed to delete series with ID %d: %w", seriesID, err)
 	}
-	defer resp.Body.Close()
-
-	// Handle non-OK HTTP responses.
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete series with ID %d. Status code: %d", seriesID, resp.StatusCode)
-	}
-
 	return nil
 }
 
 // DeleteEpisodeFiles deletes the specified episode files by their IDs from the Sonarr API.
-func (c *SonarrClient) DeleteEpisodeFiles(episodeFiles []EpisodeFile) error {
+func (c *SonarrClient) DeleteEpisodeFiles(ctx context.Context, episodeFiles []EpisodeFile) error {
 	// Extract episode file IDs from the provided episode files.
 	var episodeFileIds []int
 	for _, file := range episodeFiles {
@@ -169,38 +81,102 @@ func (c *SonarrClient) DeleteEpisodeFiles(episodeFiles []EpisodeFile) error {
 		"episodeFileIds": episodeFileIds,
 	}
 
-	// Marshal the request body to JSON.
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %v", err)
+	if err := c.rest.Delete(ctx, "/episodefile/bulk", requestBody); err != nil {
+		return fmt.Errorf("failed to delete episode files: %w", err)
 	}
+	return nil
+}
+
+// GetEpisodes fetches all episodes of a series from the Sonarr API.
+func (c *SonarrClient) GetEpisodes(ctx context.Context, seriesID int) ([]Episode, error) {
+	var episodes []Episode
+	if err := c.rest.Get(ctx, fmt.Sprintf("/episode?seriesId=%d", seriesID), &episodes); err != nil {
+		return nil, fmt.Errorf("failed to fetch episodes: %w", err)
+	}
+	return episodes, nil
+}
+
+// SetEpisodesMonitored sets the monitored state of the given episodes in a single request.
+func (c *SonarrClient) SetEpisodesMonitored(ctx context.Context, episodeIDs []int, monitored bool) error {
+	requestBody := map[string]interface{}{
+		"episodeIds": episodeIDs,
+		"monitored":  monitored,
+	}
+	if err := c.rest.Put(ctx, "/episode/monitor", requestBody, nil); err != nil {
+		return fmt.Errorf("failed to update episode monitoring: %w", err)
+	}
+	return nil
+}
+
+// LookupSeries searches Sonarr's metadata for series. term is a title or "tvdb:<id>".
+// Series already in the library have an ID.
+func (c *SonarrClient) LookupSeries(ctx context.Context, term string) ([]Series, error) {
+	var series []Series
+	if err := c.rest.Get(ctx, "/series/lookup?term="+url.QueryEscape(term), &series); err != nil {
+		return nil, fmt.Errorf("error looking up series %q: %w", term, err)
+	}
+	return series, nil
+}
 
-	// Construct the API endpoint.
-	endpoint := fmt.Sprintf("%s/episodefile/bulk", c.baseURL)
+// AddSeries adds a series to Sonarr.
+func (c *SonarrClient) AddSeries(ctx context.Context, series NewSeries) (Series, error) {
+	var added Series
+	if err := c.rest.Post(ctx, "/series", series, &added); err != nil {
+		return Series{}, fmt.Errorf("failed to add series '%s': %w", series.Title, err)
+	}
+	return added, nil
+}
+
+// GetQualityProfiles fetches the quality profiles defined in Sonarr.
+func (c *SonarrClient) GetQualityProfiles(ctx context.Context) ([]QualityProfile, error) {
+	var profiles []QualityProfile
+	if err := c.rest.Get(ctx, "/qualityprofile", &profiles); err != nil {
+		return nil, fmt.Errorf("error fetching quality profiles: %w", err)
+	}
+	return profiles, nil
+}
 
-	// Create the HTTP DELETE request.
-	req, err := http.NewRequest("DELETE", endpoint, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+// GetRootFolders fetches the root folders defined in Sonarr.
+func (c *SonarrClient) GetRootFolders(ctx context.Context) ([]RootFolder, error) {
+	var folders []RootFolder
+	if err := c.rest.Get(ctx, "/rootfolder", &folders); err != nil {
+		return nil, fmt.Errorf("error fetching root folders: %w", err)
 	}
+	return folders, nil
+}
 
-	// Set required headers.
-	c.setHeaders(req)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "*/*")
+// GetQualityDefinitions fetches the size limits of every quality.
+func (c *SonarrClient) GetQualityDefinitions(ctx context.Context) ([]QualityDefinition, error) {
+	var definitions []QualityDefinition
+	if err := c.rest.Get(ctx, "/qualitydefinition", &definitions); err != nil {
+		return nil, fmt.Errorf("error fetching quality definitions: %w", err)
+	}
+	return definitions, nil
+}
 
-	// Execute the HTTP request.
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete episode files: %v", err)
+// GetDiskSpace fetches the free space of the disks Sonarr can see.
+func (c *SonarrClient) GetDiskSpace(ctx context.Context) ([]DiskSpace, error) {
+	var disks []DiskSpace
+	if err := c.rest.Get(ctx, "/diskspace", &disks); err != nil {
+		return nil, fmt.Errorf("error fetching disk space: %w", err)
 	}
-	defer resp.Body.Close()
+	return disks, nil
+}
 
-	// Handle non-OK HTTP responses.
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete episode files. Status code: %d", resp.StatusCode)
+// GetTags fetches the tags defined in Sonarr.
+func (c *SonarrClient) GetTags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if err := c.rest.Get(ctx, "/tag", &tags); err != nil {
+		return nil, fmt.Errorf("error fetching tags: %w", err)
 	}
+	return tags, nil
+}
 
-	return nil
+// GetStatus retrieves the version information of Sonarr. It is a cheap way to check the url and API key.
+func (c *SonarrClient) GetStatus(ctx context.Context) (Status, error) {
+	var status Status
+	if err := c.rest.Get(ctx, "/system/status", &status); err != nil {
+		return Status{}, fmt.Errorf("failed to fetch status: %w", err)
+	}
+	return status, nil
 }
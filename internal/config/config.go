@@ -1,45 +1,333 @@
 package config
 
 import (
-	"log"
-	"os/user"
+	"errors"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// DefaultInstance is the name of the instance configured directly below radarr or sonarr.
+const DefaultInstance = "default"
+
+// Instance is one Radarr or Sonarr server.
+type Instance struct {
+	Name    string
+	URL     string
+	APIKey  string
+	Options rest.Options
+	// Is4K and ServerID describe how Overseer knows this server, so that only the
+	// requests sent to it are cleaned up. A nil ServerID matches any server.
+	Is4K     bool
+	ServerID *int
+	// QualityProfile and RootFolder are the defaults for adding titles: a profile name or ID and a path.
+	QualityProfile string
+	RootFolder     string
+}
+
 // Configuration holds the necessary API configuration.
 type Configuration struct {
-	RadarrURL      string
-	RadarrAPIKey   string
-	OverseerURL    string
-	OverseerAPIKey string
-	SonarrAPIKey   string
-	SonarrURL      string
-}
-
-// InitConfig initializes the configuration using viper.
-func InitConfig() {
-	viper.SetConfigName(".fcli-config")
-	usr, err := user.Current()
-	if err != nil {
-		log.Fatal(err)
-	}
-	viper.AddConfigPath(usr.HomeDir)
+	RadarrInstances []Instance
+	SonarrInstances []Instance
+	OverseerURL     string
+	OverseerAPIKey  string
+	OverseerOptions rest.Options
+	// OverseerCleanup is how Overseer is cleaned up after deletions, see cleanup.Strategies.
+	OverseerCleanup string
+	Instances       []string
+	Output          string
+	Columns         []string
+	// ExpiryTag and ExpiryTTL configure expiring requests: titles tagged ExpiryTag are deleted
+	// ExpiryTTL after they became available, titles tagged "<ExpiryTag>-<age>" after age.
+	ExpiryTag string
+	ExpiryTTL string
+	// QuotaDefault is every Overseer user's storage budget, e.g. "500GB"; QuotaUsers overrides
+	// it by user ID or name, where "unlimited" exempts a user.
+	QuotaDefault string
+	QuotaUsers   map[string]string
+	// GateMinFree is the space "requests gate" keeps free on every disk, e.g. "100GB".
+	GateMinFree string
+}
+
+// EnvPrefix is the prefix of environment variables overriding config keys, e.g.
+// FCLI_RADARR_URL for radarr.url or FCLI_RADARR_API_KEY for radarr.api_key.
+const EnvPrefix = "FCLI"
+
+// DefaultPath is where a new config file is written: $XDG_CONFIG_HOME/fcli/config.yaml,
+// or ~/.config/fcli/config.yaml when XDG_CONFIG_HOME is unset.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "fcli", "config.yaml")
+}
+
+// searchPaths lists the config file locations in the order they are tried. The legacy
+// ~/.fcli-config file is still read when no XDG config exists.
+func searchPaths() []string {
+	home, _ := os.UserHomeDir()
+	xdg := filepath.Dir(DefaultPath())
+	return []string{
+		filepath.Join(xdg, "config.yaml"),
+		filepath.Join(xdg, "config.yml"),
+		filepath.Join(home, ".fcli-config.yaml"),
+		filepath.Join(home, ".fcli-config.yml"),
+		filepath.Join(home, ".fcli-config"),
+	}
+}
+
+// path is the config file in use, set by InitConfig.
+var path string
+
+// Path returns the config file in use, or the file a new config would be written to.
+func Path() string {
+	if path != "" {
+		return path
+	}
+	return DefaultPath()
+}
+
+// Exists reports whether the config file returned by Path exists.
+func Exists() bool {
+	_, err := os.Stat(Path())
+	return err == nil
+}
+
+// InitConfig reads the config file and enables FCLI_* environment overrides. file overrides
+// the search for a config file (--config). A missing config file is not an error, since
+// everything can also be set through the environment.
+func InitConfig(file string) error {
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	viper.SetConfigType("yaml")
 
+	path = file
+	if path == "" {
+		for _, candidate := range searchPaths() {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	viper.SetConfigFile(path)
 	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalf("Error reading config file, %s", err)
+		// An explicit --config file may not exist yet, e.g. for "fcli config init".
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// lookup returns the first of the given keys that is set, or "" when none is.
+// It lets the documented base_url/api_key spelling be used next to url/apiKey.
+func lookup(section string, names ...string) string {
+	for _, name := range names {
+		if viper.IsSet(section + "." + name) {
+			return section + "." + name
+		}
+	}
+	return ""
+}
+
+var (
+	urlKeys    = []string{"url", "base_url"}
+	apiKeyKeys = []string{"apiKey", "api_key"}
+)
+
+// transportOptions reads the HTTP transport settings of a service, e.g. radarr.timeout
+// or sonarr.insecureSkipVerify, falling back to the defaults for unset values. Sections are
+// searched in order, so an instance can override the settings of its service.
+func transportOptions(sections ...string) rest.Options {
+	key := func(name string) string {
+		for _, section := range sections {
+			if viper.IsSet(section + "." + name) {
+				return section + "." + name
+			}
+		}
+		return ""
+	}
+
+	opts := rest.DefaultOptions()
+	if k := key("timeout"); k != "" {
+		opts.Timeout = viper.GetDuration(k)
+	}
+	if k := key("retries"); k != "" {
+		opts.Retries = viper.GetInt(k)
+	}
+	if k := key("retryWait"); k != "" {
+		opts.RetryWait = viper.GetDuration(k)
+	}
+	if k := key("caFile"); k != "" {
+		opts.CAFile = viper.GetString(k)
+	}
+	if k := key("insecureSkipVerify"); k != "" {
+		opts.InsecureSkipVerify = viper.GetBool(k)
+	}
+	if k := key("proxy"); k != "" {
+		opts.Proxy = viper.GetString(k)
+	}
+	opts.RecordDir = viper.GetString("record")
+	opts.ReplayDir = viper.GetString("replay")
+	return opts
+}
+
+// instances reads the instances of a service. A service configured with a url directly
+// below it has a single instance named "default"; otherwise every sub-section with a url
+// is an instance, e.g. radarr.default and radarr.uhd.
+func instances(service string) []Instance {
+	if lookup(service, urlKeys...) != "" {
+		return []Instance{readInstance(service, DefaultInstance, service)}
+	}
+
+	var names []string
+	for name := range viper.GetStringMap(service) {
+		if lookup(service+"."+name, urlKeys...) != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// The default instance is listed first, the others alphabetically.
+		if (names[i] == DefaultInstance) != (names[j] == DefaultInstance) {
+			return names[i] == DefaultInstance
+		}
+		return names[i] < names[j]
+	})
+
+	var result []Instance
+	for _, name := range names {
+		result = append(result, readInstance(service, name, service+"."+name, service))
+	}
+	return result
+}
+
+// readInstance reads one instance from the first of sections; transport settings fall back to the others.
+func readInstance(service, name string, sections ...string) Instance {
+	section := sections[0]
+	instance := Instance{
+		Name:    name,
+		URL:     viper.GetString(lookup(section, urlKeys...)),
+		APIKey:  viper.GetString(lookup(section, apiKeyKeys...)),
+		Options: transportOptions(sections...),
+		Is4K:    viper.GetBool(section + ".is4k"),
+	}
+	// Add defaults may be set for the whole service and overridden per instance.
+	for i := len(sections) - 1; i >= 0; i-- {
+		if viper.IsSet(sections[i] + ".qualityProfile") {
+			instance.QualityProfile = viper.GetString(sections[i] + ".qualityProfile")
+		}
+		if viper.IsSet(sections[i] + ".rootFolder") {
+			instance.RootFolder = viper.GetString(sections[i] + ".rootFolder")
+		}
+	}
+	if viper.IsSet(section + ".serverId") {
+		serverID := viper.GetInt(section + ".serverId")
+		instance.ServerID = &serverID
+	}
+	return instance
+}
+
+// Radarr returns the Radarr instances selected with --instance (all of them by default).
+// A non-empty apiKey replaces the configured key of every selected instance.
+func (c *Configuration) Radarr(apiKey string) ([]Instance, error) {
+	return selectInstances("radarr", c.RadarrInstances, c.Instances, apiKey)
+}
+
+// Sonarr returns the Sonarr instances selected with --instance (all of them by default).
+// A non-empty apiKey replaces the configured key of every selected instance.
+func (c *Configuration) Sonarr(apiKey string) ([]Instance, error) {
+	return selectInstances("sonarr", c.SonarrInstances, c.Instances, apiKey)
+}
+
+// Both returns the Radarr and Sonarr instances selected with --instance, for commands that work
+// across both services. A service without any configured or matching instance is left out; only
+// names that match no instance of either service are an error.
+func (c *Configuration) Both(radarrAPIKey, sonarrAPIKey string) ([]Instance, []Instance, error) {
+	radarr := pickInstances(c.RadarrInstances, c.Instances, radarrAPIKey)
+	sonarr := pickInstances(c.SonarrInstances, c.Instances, sonarrAPIKey)
+	if len(c.Instances) > 0 && len(radarr) == 0 && len(sonarr) == 0 {
+		return nil, nil, fmt.Errorf("no radarr or sonarr instance named %s (configured: radarr: %s; sonarr: %s)", strings.Join(c.Instances, ", "), instanceNames(c.RadarrInstances), instanceNames(c.SonarrInstances))
+	}
+	return radarr, sonarr, nil
+}
+
+func selectInstances(service string, all []Instance, names []string, apiKey string) ([]Instance, error) {
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no %s instance is configured; run \"fcli config init\" or set %s_%s_URL", service, EnvPrefix, strings.ToUpper(service))
+	}
+	selected := pickInstances(all, names, apiKey)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no %s instance named %s (configured: %s)", service, strings.Join(names, ", "), instanceNames(all))
+	}
+	return selected, nil
+}
+
+// pickInstances returns the instances with one of the given names, or all of them without names.
+// A non-empty apiKey replaces the configured key of every picked instance.
+func pickInstances(all []Instance, names []string, apiKey string) []Instance {
+	var selected []Instance
+	for _, instance := range all {
+		if len(names) > 0 && !containsName(names, instance.Name) {
+			continue
+		}
+		if len(apiKey) > 0 {
+			instance.APIKey = apiKey
+		}
+		selected = append(selected, instance)
+	}
+	return selected
+}
+
+func instanceNames(instances []Instance) string {
+	if len(instances) == 0 {
+		return "none"
+	}
+	var names []string
+	for _, instance := range instances {
+		names = append(names, instance.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
 	}
+	return false
 }
 
 // GetConfig returns a Configuration struct populated with values from viper.
 func GetConfig() *Configuration {
 	return &Configuration{
-		RadarrURL:      viper.GetString("radarr.url"),
-		RadarrAPIKey:   viper.GetString("radarr.apiKey"),
-		OverseerURL:    viper.GetString("overseer.url"),
-		OverseerAPIKey: viper.GetString("overseer.apiKey"),
-		SonarrAPIKey:   viper.GetString("sonarr.apiKey"),
-		SonarrURL:      viper.GetString("sonarr.url"),
+		RadarrInstances: instances("radarr"),
+		SonarrInstances: instances("sonarr"),
+		OverseerURL:     viper.GetString(lookup("overseer", urlKeys...)),
+		OverseerAPIKey:  viper.GetString(lookup("overseer", apiKeyKeys...)),
+		OverseerOptions: transportOptions("overseer"),
+		OverseerCleanup: viper.GetString("overseer.cleanup"),
+		ExpiryTag:       viper.GetString("expiry.tag"),
+		ExpiryTTL:       viper.GetString("expiry.ttl"),
+		QuotaDefault:    viper.GetString("quota.default"),
+		QuotaUsers:      viper.GetStringMapString("quota.users"),
+		GateMinFree:     viper.GetString("gate.minFree"),
+		Instances:       viper.GetStringSlice("instance"),
+		Output:          viper.GetString("output"),
+		Columns:         viper.GetStringSlice("columns"),
 	}
 }
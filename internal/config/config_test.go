@@ -0,0 +1,81 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBoth(t *testing.T) {
+	conf := &Configuration{
+		RadarrInstances: []Instance{{Name: "main"}, {Name: "4k"}},
+		SonarrInstances: []Instance{{Name: "main"}, {Name: "anime"}},
+	}
+	tests := []struct {
+		name       string
+		instances  []string
+		wantRadarr []string
+		wantSonarr []string
+		wantErr    bool
+	}{
+		{"all by default", nil, []string{"main", "4k"}, []string{"main", "anime"}, false},
+		{"name in both services", []string{"main"}, []string{"main"}, []string{"main"}, false},
+		{"name only in radarr", []string{"4k"}, []string{"4k"}, nil, false},
+		{"name only in sonarr", []string{"anime"}, nil, []string{"anime"}, false},
+		{"names split across services", []string{"4k", "anime"}, []string{"4k"}, []string{"anime"}, false},
+		{"unknown name", []string{"uhd"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := *conf
+			c.Instances = tt.instances
+
+			radarr, sonarr, err := c.Both("", "")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got := names(radarr); !slices.Equal(got, tt.wantRadarr) {
+				t.Errorf("radarr = %q, want %q", got, tt.wantRadarr)
+			}
+			if got := names(sonarr); !slices.Equal(got, tt.wantSonarr) {
+				t.Errorf("sonarr = %q, want %q", got, tt.wantSonarr)
+			}
+		})
+	}
+}
+
+func TestBothWithoutService(t *testing.T) {
+	conf := &Configuration{SonarrInstances: []Instance{{Name: "main"}}}
+
+	radarr, sonarr, err := conf.Both("", "key")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(radarr) != 0 {
+		t.Errorf("radarr = %v, want none", radarr)
+	}
+	if len(sonarr) != 1 || sonarr[0].APIKey != "key" {
+		t.Errorf("sonarr = %v, want main with the given API key", sonarr)
+	}
+}
+
+func TestSonarrNameOnlyInRadarr(t *testing.T) {
+	// Commands for a single service still reject a name that service doesn't have.
+	conf := &Configuration{
+		RadarrInstances: []Instance{{Name: "4k"}},
+		SonarrInstances: []Instance{{Name: "main"}},
+		Instances:       []string{"4k"},
+	}
+	if _, err := conf.Sonarr(""); err == nil {
+		t.Error("Sonarr accepted an instance only Radarr has")
+	}
+}
+
+func names(instances []Instance) []string {
+	var result []string
+	for _, instance := range instances {
+		result = append(result, instance.Name)
+	}
+	return result
+}
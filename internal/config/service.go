@@ -0,0 +1,335 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/radarr"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/render"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// starterService is one service section of a config file written by HandleInit.
+type starterService struct {
+	URL    string `yaml:"url"`
+	APIKey string `yaml:"apiKey"`
+}
+
+type starterConfig struct {
+	Radarr   *starterService `yaml:"radarr,omitempty"`
+	Sonarr   *starterService `yaml:"sonarr,omitempty"`
+	Overseer *starterService `yaml:"overseer,omitempty"`
+}
+
+// HandleInit asks for the url and API key of every service and writes a starter config file.
+// An existing file is only replaced when force is set.
+func HandleInit(force bool) error {
+	target := Path()
+	if Exists() && !force {
+		return fmt.Errorf("%s already exists; use --force to replace it or \"fcli config set\" to change single keys", target)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	prompt := func(question, fallback string) string {
+		if fallback != "" {
+			fmt.Printf(Green+"%s [%s]: "+Reset, question, fallback)
+		} else {
+			fmt.Printf(Green+"%s: "+Reset, question)
+		}
+		input, _ := reader.ReadString('\n')
+		if input = strings.TrimSpace(input); input != "" {
+			return input
+		}
+		return fallback
+	}
+	service := func(name, defaultURL string) *starterService {
+		fmt.Printf("%s (leave the API key empty to skip it)\n", name)
+		s := &starterService{
+			URL:    prompt("  url", defaultURL),
+			APIKey: prompt("  API key", ""),
+		}
+		if s.APIKey == "" {
+			return nil
+		}
+		return s
+	}
+
+	starter := starterConfig{
+		Radarr:   service("Radarr", "http://localhost:7878/api/v3"),
+		Sonarr:   service("Sonarr", "http://localhost:8989/api/v3"),
+		Overseer: service("Overseer", "http://localhost:5055/api/v1"),
+	}
+	data, err := marshal(starter)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(target, data); err != nil {
+		return err
+	}
+	fmt.Printf(Green+"Config written to %s. Run \"fcli config validate\" to check it.\n"+Reset, target)
+	return nil
+}
+
+// marshal encodes YAML with the two space indentation used in the README examples.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFile writes a config file readable only by the current user, since it holds API keys.
+func writeFile(target string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0o600)
+}
+
+// mask hides all but the last four characters of a secret.
+func mask(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+// serviceEntry is one configured service instance. Overseer always has a single instance.
+type serviceEntry struct {
+	Service  string
+	Instance Instance
+}
+
+// services lists every configured service instance.
+func (c *Configuration) services() []serviceEntry {
+	var entries []serviceEntry
+	for _, instance := range c.RadarrInstances {
+		entries = append(entries, serviceEntry{"radarr", instance})
+	}
+	for _, instance := range c.SonarrInstances {
+		entries = append(entries, serviceEntry{"sonarr", instance})
+	}
+	if c.OverseerURL != "" || c.OverseerAPIKey != "" {
+		entries = append(entries, serviceEntry{"overseer", Instance{Name: DefaultInstance, URL: c.OverseerURL, APIKey: c.OverseerAPIKey, Options: c.OverseerOptions}})
+	}
+	return entries
+}
+
+// HandleShow prints the effective configuration, including environment overrides, with API keys masked.
+func HandleShow() error {
+	conf := GetConfig()
+	r := render.New(conf.Output, conf.Columns)
+	if Exists() {
+		r.Infof("Config file: %s\n", Path())
+	} else {
+		r.Infof("Config file: %s (not found, using %s_* environment variables only)\n", Path(), EnvPrefix)
+	}
+
+	var records []render.Record
+	for _, s := range conf.services() {
+		var serverID any
+		if s.Instance.ServerID != nil {
+			serverID = *s.Instance.ServerID
+		}
+		records = append(records, render.Record{
+			{Name: "service", Value: s.Service},
+			{Name: "instance", Value: s.Instance.Name},
+			{Name: "url", Value: s.Instance.URL},
+			{Name: "apiKey", Value: mask(s.Instance.APIKey)},
+			{Name: "is4k", Value: s.Instance.Is4K},
+			{Name: "serverId", Value: serverID},
+			{Name: "timeout", Value: s.Instance.Options.Timeout.String()},
+			{Name: "retries", Value: s.Instance.Options.Retries},
+			{Name: "retryWait", Value: s.Instance.Options.RetryWait.String()},
+			{Name: "caFile", Value: s.Instance.Options.CAFile},
+			{Name: "insecureSkipVerify", Value: s.Instance.Options.InsecureSkipVerify},
+			{Name: "proxy", Value: s.Instance.Options.Proxy},
+		})
+	}
+	return r.Render(records, "service", "instance", "url", "apiKey", "timeout", "retries")
+}
+
+// checkURL reports problems with a configured service url that don't need a request to find.
+func checkURL(raw string) error {
+	if raw == "" {
+		return errors.New("url is not set")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url %q has no host", raw)
+	}
+	return nil
+}
+
+// HandleValidate checks the url and API key of every configured service by calling its status endpoint.
+func HandleValidate(ctx context.Context) error {
+	conf := GetConfig()
+	r := render.New(conf.Output, conf.Columns)
+
+	entries := conf.services()
+	if len(entries) == 0 {
+		return fmt.Errorf("no services are configured in %s; run \"fcli config init\"", Path())
+	}
+
+	var failures []error
+	var records []render.Record
+	for _, s := range entries {
+		version, err := validate(ctx, s.Service, s.Instance)
+		status := "ok"
+		message := ""
+		if err != nil {
+			status = "error"
+			message = err.Error()
+			failures = append(failures, fmt.Errorf("%s (%s): %w", s.Service, s.Instance.Name, err))
+		}
+		records = append(records, render.Record{
+			{Name: "service", Value: s.Service},
+			{Name: "instance", Value: s.Instance.Name},
+			{Name: "url", Value: s.Instance.URL},
+			{Name: "status", Value: status},
+			{Name: "version", Value: version},
+			{Name: "error", Value: message},
+		})
+	}
+	if err := r.Render(records); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+	r.Infof(Green + "All services are reachable and accept their API keys.\n" + Reset)
+	return nil
+}
+
+// validate checks one service and returns its version.
+func validate(ctx context.Context, service string, instance Instance) (string, error) {
+	if err := checkURL(instance.URL); err != nil {
+		return "", err
+	}
+	if instance.APIKey == "" {
+		return "", errors.New("API key is not set")
+	}
+	// A single attempt is enough to tell whether the settings work.
+	opts := instance.Options
+	opts.Retries = 0
+	switch service {
+	case "radarr":
+		status, err := radarr.NewRadarrClient(instance.URL, instance.APIKey, opts).GetStatus(ctx)
+		return status.Version, err
+	case "sonarr":
+		status, err := sonarr.NewSonarrClient(instance.URL, instance.APIKey, opts).GetStatus(ctx)
+		return status.Version, err
+	default:
+		status, err := overseer.NewOverseerClient(instance.URL, instance.APIKey, opts).GetStatus(ctx)
+		return status.Version, err
+	}
+}
+
+// HandleSet sets a single key, e.g. radarr.uhd.apiKey, in the config file. Comments and the
+// order of the existing keys are kept.
+func HandleSet(key, value string) error {
+	keys := strings.Split(key, ".")
+	for _, k := range keys {
+		if k == "" {
+			return fmt.Errorf("invalid key %q", key)
+		}
+	}
+
+	target := Path()
+	var doc yaml.Node
+	data, err := os.ReadFile(target)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("error reading config file %s: %w", target, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if err := setKey(&doc, keys, value); err != nil {
+		return err
+	}
+
+	data, err = marshal(&doc)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(target, data); err != nil {
+		return err
+	}
+	fmt.Printf(Green+"Set %s in %s.\n"+Reset, key, target)
+	return nil
+}
+
+// setKey sets the value at a key path in a YAML document, creating missing sections.
+// Existing keys are matched case-insensitively, like viper does.
+func setKey(doc *yaml.Node, keys []string, value string) error {
+	if len(doc.Content) == 0 {
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.MappingNode})
+	}
+	node := doc.Content[0]
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is a value, not a section", strings.Join(keys[:i], "."))
+		}
+		last := i == len(keys)-1
+
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if strings.EqualFold(node.Content[j].Value, key) {
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			if last {
+				child = &yaml.Node{Kind: yaml.ScalarNode}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		if last {
+			if child.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s is a section; set one of its keys instead", key)
+			}
+			// Without a tag the value is written plainly, so "true" or "3" keep their type.
+			child.Value, child.Tag, child.Style = value, "", 0
+			if value == "" {
+				child.Style = yaml.DoubleQuotedStyle
+			}
+		}
+		node = child
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package movies
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/add"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/radarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var imdbID = regexp.MustCompile(`^tt\d+$`)
+
+// lookupTerm turns a TMDB ID, an IMDb ID or a title into a Radarr lookup term.
+func lookupTerm(query string) string {
+	query = strings.TrimSpace(query)
+	lower := strings.ToLower(query)
+	if strings.HasPrefix(lower, "tmdb:") || strings.HasPrefix(lower, "imdb:") {
+		return query
+	}
+	if _, err := strconv.Atoi(query); err == nil {
+		return "tmdb:" + query
+	}
+	if imdbID.MatchString(lower) {
+		return "imdb:" + lower
+	}
+	return query
+}
+
+// HandleAdd looks a movie up by TMDB ID, IMDb ID or title and adds it to Radarr, or requests it
+// through Overseer. A title matching several movies asks which one to add.
+func HandleAdd(ctx context.Context, radarrAPIKey, overseerAPIKey, query string, opts add.Options) error {
+	// Get configuration
+	conf := config.GetConfig()
+	instances, err := conf.Radarr(radarrAPIKey)
+	if err != nil {
+		return err
+	}
+	if len(instances) > 1 {
+		return fmt.Errorf("several Radarr instances are selected; pick the one to add to with --instance")
+	}
+	instance := instances[0]
+	r := render.New(conf.Output, conf.Columns)
+	client := radarr.NewRadarrClient(instance.URL, instance.APIKey, instance.Options)
+
+	results, err := client.LookupMovie(ctx, lookupTerm(query))
+	if err != nil {
+		return err
+	}
+	movie, err := pickMovie(r, query, results)
+	if err != nil || movie == nil {
+		return err
+	}
+	title := fmt.Sprintf("%s (%d)", movie.Title, movie.Year)
+	if movie.ID != 0 {
+		r.Infof(Yellow+"'%s' is already in %s.\n"+Reset, title, radarrName(instance))
+		return nil
+	}
+
+	profiles, folders, err := choices(ctx, client)
+	if err != nil {
+		return err
+	}
+	profileID, rootFolder, err := add.Resolve("Radarr", instance, profiles, folders, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Overseer {
+		if len(overseerAPIKey) > 0 {
+			conf.OverseerAPIKey = overseerAPIKey
+		}
+		overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+		request := overseer.NewRequest{MediaType: "movie", MediaID: movie.TMDBID, Is4K: instance.Is4K, ServerID: instance.ServerID, ProfileID: profileID, RootFolder: rootFolder}
+		return add.Request(ctx, overseerClient, request, title, opts.User)
+	}
+
+	minimumAvailability := movie.MinimumAvailability
+	if minimumAvailability == "" {
+		minimumAvailability = "released"
+	}
+	_, err = client.AddMovie(ctx, radarr.NewMovie{
+		Title:               movie.Title,
+		Year:                movie.Year,
+		TMDBID:              movie.TMDBID,
+		QualityProfileID:    profileID,
+		RootFolderPath:      rootFolder,
+		Monitored:           true,
+		MinimumAvailability: minimumAvailability,
+		Tags:                []int{},
+		AddOptions:          radarr.AddOptions{Monitor: "movieOnly", SearchForMovie: opts.Search, AddMethod: "manual"},
+	})
+	if err != nil {
+		return err
+	}
+	plan.Annotate(fmt.Sprintf("Add movie '%s' to %s", title, radarrName(instance)), 0)
+	if opts.Search {
+		fmt.Printf(Green+"'%s' was added to %s and is being searched for.\n"+Reset, title, radarrName(instance))
+	} else {
+		fmt.Printf(Green+"'%s' was added to %s.\n"+Reset, title, radarrName(instance))
+	}
+	return nil
+}
+
+// pickMovie returns the only lookup result, or asks which one to use. It returns nil when
+// nothing was picked.
+func pickMovie(r *render.Renderer, query string, results []radarr.Movie) (*radarr.Movie, error) {
+	switch len(results) {
+	case 0:
+		return nil, fmt.Errorf("no movies found for %q", query)
+	case 1:
+		return &results[0], nil
+	}
+
+	var records []render.Record
+	for i, movie := range results {
+		records = append(records, render.Record{
+			{Name: "index", Value: i + 1},
+			{Name: "title", Value: movie.Title},
+			{Name: "year", Value: movie.Year},
+			{Name: "tmdbId", Value: movie.TMDBID},
+			{Name: "imdbId", Value: movie.IMDbID},
+			{Name: "inLibrary", Value: movie.ID != 0},
+		})
+	}
+	if err := r.Render(records, "index", "title", "year", "tmdbId", "inLibrary"); err != nil {
+		return nil, err
+	}
+	fmt.Print(Green + "Select the movie to add (empty = cancel): " + Reset)
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		fmt.Println("No movie selected. Exiting.")
+		return nil, nil
+	}
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(results) {
+		return nil, fmt.Errorf("invalid selection: %s", input)
+	}
+	return &results[index-1], nil
+}
+
+// choices returns Radarr's quality profiles and root folders.
+func choices(ctx context.Context, client *radarr.RadarrClient) ([]add.Choice, []add.Choice, error) {
+	profiles, err := client.GetQualityProfiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, err := client.GetRootFolders(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var profileChoices, folderChoices []add.Choice
+	for _, profile := range profiles {
+		profileChoices = append(profileChoices, add.Choice{ID: profile.ID, Name: profile.Name})
+	}
+	for _, folder := range folders {
+		folderChoices = append(folderChoices, add.Choice{ID: folder.ID, Name: folder.Path})
+	}
+	return profileChoices, folderChoices, nil
+}
@@ -0,0 +1,184 @@
+package movies
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/radarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/render"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PruneCriteria describes which movies should be selected by the prune command.
+// Zero values mean "don't filter on this field"; all set criteria must match.
+type PruneCriteria struct {
+	AddedBefore  time.Time
+	MinSizeGB    float64
+	RatingBelow  float64
+	RatingSource string
+	Genres       []string
+	Tags         []string
+	Monitored    *bool
+	HasFile      *bool
+}
+
+// rating returns the rating of a movie for the given source and whether it has any votes.
+func rating(movie radarr.Movie, source string) (float64, bool) {
+	var r radarr.Rating
+	switch strings.ToLower(source) {
+	case "tmdb":
+		r = movie.Ratings.TMDB
+	case "metacritic":
+		r = movie.Ratings.Metacritic
+	case "rottentomatoes":
+		r = movie.Ratings.RottenTomatoes
+	default:
+		r = movie.Ratings.IMDb
+	}
+	return float64(r.Value), r.Votes > 0 || r.Value > 0
+}
+
+// containsFold reports whether any of the wanted values is present in values, ignoring case.
+func containsFold(values []string, wanted []string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if strings.EqualFold(v, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsZero reports whether the criteria select every movie. RatingSource alone is not a criterion.
+func (c PruneCriteria) IsZero() bool {
+	return c.AddedBefore.IsZero() && c.MinSizeGB <= 0 && c.RatingBelow <= 0 && len(c.Genres) == 0 &&
+		len(c.Tags) == 0 && c.Monitored == nil && c.HasFile == nil
+}
+
+// Matches reports whether a movie satisfies every criterion. tagLabels maps Radarr tag IDs to labels.
+func (c PruneCriteria) Matches(movie radarr.Movie, tagLabels map[int]string) bool {
+	if !c.AddedBefore.IsZero() {
+		added, err := time.Parse(time.RFC3339, movie.Added)
+		if err != nil || !added.Before(c.AddedBefore) {
+			return false
+		}
+	}
+	if c.MinSizeGB > 0 && float64(movie.Statistics.SizeOnDisk)/(1024*1024*1024) < c.MinSizeGB {
+		return false
+	}
+	if c.RatingBelow > 0 {
+		// Unrated movies are never selected by a rating criterion.
+		value, rated := rating(movie, c.RatingSource)
+		if !rated || value >= c.RatingBelow {
+			return false
+		}
+	}
+	if len(c.Genres) > 0 && !containsFold(movie.Genres, c.Genres) {
+		return false
+	}
+	if len(c.Tags) > 0 {
+		var labels []string
+		for _, id := range movie.Tags {
+			labels = append(labels, tagLabels[id])
+		}
+		if !containsFold(labels, c.Tags) {
+			return false
+		}
+	}
+	if c.Monitored != nil && movie.Monitored != *c.Monitored {
+		return false
+	}
+	if c.HasFile != nil && movie.HasFile != *c.HasFile {
+		return false
+	}
+	return true
+}
+
+// HandlePrune selects movies by criteria and deletes them from Overseer and Radarr.
+// Without confirm it only prints a report of what would be deleted.
+func HandlePrune(ctx context.Context, radarrAPIKey, overseerAPIKey, overseerCleanup string, criteria PruneCriteria, confirm bool) error {
+	if criteria.IsZero() {
+		return fmt.Errorf("give at least one criterion such as --added-before, --min-size-gb or --rating-below; prune never selects every movie")
+	}
+	// Get configuration
+	conf := config.GetConfig()
+	instances, err := conf.Radarr(radarrAPIKey)
+	if err != nil {
+		return err
+	}
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+	printEndpoints(r, instances)
+
+	radarrMovies, err := FetchMovies(ctx, instances)
+	if err != nil {
+		return err
+	}
+
+	// Tag IDs are local to an instance, so labels are looked up per instance.
+	tagLabels := map[string]map[int]string{}
+	if len(criteria.Tags) > 0 {
+		for _, movie := range radarrMovies {
+			if _, ok := tagLabels[movie.Instance.Name]; ok {
+				continue
+			}
+			labels, err := movie.TagLabels(ctx)
+			if err != nil {
+				return err
+			}
+			tagLabels[movie.Instance.Name] = labels
+		}
+	}
+
+	var selected []Movie
+	for _, movie := range radarrMovies {
+		if criteria.Matches(movie.Movie, tagLabels[movie.Instance.Name]) {
+			selected = append(selected, movie)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Statistics.SizeOnDisk > selected[j].Statistics.SizeOnDisk
+	})
+
+	if len(selected) == 0 {
+		r.Infof("No movies match the given criteria.\n")
+		return nil
+	}
+
+	var records []render.Record
+	var totalSize int
+	for _, movie := range selected {
+		totalSize += movie.Statistics.SizeOnDisk
+		records = append(records, MovieRecord(movie))
+	}
+	if err := r.Render(records, withInstance(instances, "title", "added", "sizeOnDisk", "monitored", "hasFile")...); err != nil {
+		return err
+	}
+	r.Infof("%d movies matched, %.2f GB in total.\n", len(selected), float64(totalSize)/(1024*1024*1024))
+
+	if !confirm {
+		r.Infof(Yellow + "Dry run: nothing was deleted. Re-run with --yes to delete these movies.\n" + Reset)
+		return nil
+	}
+
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+	if err != nil {
+		return err
+	}
+
+	for _, movie := range selected {
+		DeleteMovie(ctx, overseerData, movie)
+	}
+	return nil
+}
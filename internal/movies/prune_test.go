@@ -0,0 +1,40 @@
+package movies
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneCriteriaIsZero(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name     string
+		criteria PruneCriteria
+		want     bool
+	}{
+		{"empty", PruneCriteria{}, true},
+		{"rating source only", PruneCriteria{RatingSource: "tmdb"}, true},
+		{"added before", PruneCriteria{AddedBefore: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"min size", PruneCriteria{MinSizeGB: 30}, false},
+		{"rating below", PruneCriteria{RatingBelow: 5}, false},
+		{"genre", PruneCriteria{Genres: []string{"Horror"}}, false},
+		{"tag", PruneCriteria{Tags: []string{"kids"}}, false},
+		{"monitored", PruneCriteria{Monitored: &yes}, false},
+		{"has file", PruneCriteria{HasFile: &yes}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.criteria.IsZero(); got != tt.want {
+				t.Errorf("IsZero() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePruneRejectsEmptyCriteria(t *testing.T) {
+	err := HandlePrune(context.Background(), "", "", "", PruneCriteria{RatingSource: "imdb"}, true)
+	if err == nil {
+		t.Fatal("HandlePrune accepted empty criteria")
+	}
+}
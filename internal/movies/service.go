@@ -2,15 +2,20 @@ package movies
 
 import (
 	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
 	"flashbacklabsio/fcli/internal/clients/overseer"
 	"flashbacklabsio/fcli/internal/clients/radarr"
+	"flashbacklabsio/fcli/internal/clients/rest"
 	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
 	"fmt"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"text/tabwriter"
+	"time"
 )
 
 const (
@@ -30,6 +35,65 @@ func HandleMoviesCommand() {
 	fmt.Println("Movie management subcommands can be found here. Supply --help to see available movie commands.")
 }
 
+// Movie is a Radarr movie together with the instance it was found on.
+type Movie struct {
+	radarr.Movie
+	Instance config.Instance
+	client   *radarr.RadarrClient
+}
+
+// FetchMovies gets the movies of every given Radarr instance.
+func FetchMovies(ctx context.Context, instances []config.Instance) ([]Movie, error) {
+	var all []Movie
+	for _, instance := range instances {
+		client := radarr.NewRadarrClient(instance.URL, instance.APIKey, instance.Options)
+		radarrMovies, err := client.GetMovies(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get movies from %s: %w", radarrName(instance), err)
+		}
+		for _, movie := range radarrMovies {
+			all = append(all, Movie{Movie: movie, Instance: instance, client: client})
+		}
+	}
+	return all, nil
+}
+
+// TagLabels returns the labels of the tags defined on the movie's instance, by tag ID.
+func (m Movie) TagLabels(ctx context.Context) (map[int]string, error) {
+	tags, err := m.client.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[int]string{}
+	for _, tag := range tags {
+		labels[tag.ID] = tag.Label
+	}
+	return labels, nil
+}
+
+// radarrName names an instance in messages, e.g. "Radarr" or "Radarr (uhd)".
+func radarrName(instance config.Instance) string {
+	if instance.Name == config.DefaultInstance {
+		return "Radarr"
+	}
+	return fmt.Sprintf("Radarr (%s)", instance.Name)
+}
+
+// withInstance adds the instance column to the default columns when several instances are listed.
+func withInstance(instances []config.Instance, columns ...string) []string {
+	if len(instances) < 2 {
+		return columns
+	}
+	return append([]string{"instance"}, columns...)
+}
+
+// printEndpoints prints the API endpoint of every instance.
+func printEndpoints(r *render.Renderer, instances []config.Instance) {
+	for _, instance := range instances {
+		r.Infof("%s API Endpoint %v\n", radarrName(instance), instance.URL)
+	}
+}
+
 // FindMediaItemByTmdbID searches for a media item by its TMDB ID.
 func FindMediaItemByTmdbID(tmdbID int, media []overseer.Media) (*overseer.Media, error) {
 	for _, item := range media {
@@ -37,60 +101,69 @@ func FindMediaItemByTmdbID(tmdbID int, media []overseer.Media) (*overseer.Media,
 			return &item, nil
 		}
 	}
-	return nil, fmt.Errorf("no matching MovieItem found for TMDBID %d", tmdbID)
+	return nil, fmt.Errorf("no matching MovieItem found for TMDBID %d; run \"fcli reconcile\" to find titles Overseer doesn't know about", tmdbID)
 }
 
-func HandleGet(radarrAPIKey string, overseerAPIKey string, limit int, skip int) {
-	// Initialize and get configuration
-	config.InitConfig()
+func HandleGet(ctx context.Context, radarrAPIKey string, overseerAPIKey string, limit int, skip int) error {
+	// Get configuration
 	conf := config.GetConfig()
-	if len(radarrAPIKey) > 0 {
-		conf.RadarrAPIKey = radarrAPIKey
+	instances, err := conf.Radarr(radarrAPIKey)
+	if err != nil {
+		return err
 	}
 	if len(overseerAPIKey) > 0 {
 		conf.OverseerAPIKey = overseerAPIKey
 	}
 
-	radarrClient := radarr.NewRadarrClient(conf.RadarrURL, conf.RadarrAPIKey)
-	fmt.Printf("Radarr API Endpoint %v\n", conf.RadarrURL)
-	radarrMovies, err := radarrClient.GetMovies()
+	r := render.New(conf.Output, conf.Columns)
+	printEndpoints(r, instances)
+	radarrMovies, err := FetchMovies(ctx, instances)
 	if err != nil {
-		fmt.Printf("Could not get movies: %v\n", err.Error())
-		return
+		return err
 	}
 
-	// Initialize tabwriter
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
-
-	// Print header
-	fmt.Fprintf(w, "Title\tOriginal Title\tSize on Disk (GB)\tPath\n")
-	fmt.Fprintf(w, "-----\t--------------\t------------\t-----------------\n")
-
-	// Print movie details
-	for i, movie := range radarrMovies {
-		if i >= limit {
-			break
-		}
-		sizeOnDiskGB := float64(movie.Statistics.SizeOnDisk) / (1024 * 1024 * 1024) // Convert bytes to GB
-		fmt.Fprintf(w, "%s\t%s\t%.2f GB\t%s\n", movie.Title, movie.OriginalTitle, sizeOnDiskGB, movie.MovieFile.Path)
+	var records []render.Record
+	for i := skip; i < len(radarrMovies) && i < skip+limit; i++ {
+		records = append(records, MovieRecord(radarrMovies[i]))
 	}
+	return r.Render(records, withInstance(instances, "title", "originalTitle", "sizeOnDisk", "path")...)
+}
 
-	// Flush the writer to ensure all output is printed
-	w.Flush()
+// MovieRecord describes a movie for listings, using the field names of the Radarr API.
+func MovieRecord(movie Movie) render.Record {
+	added, _ := time.Parse(time.RFC3339, movie.Added)
+	return render.Record{
+		{Name: "instance", Value: movie.Instance.Name},
+		{Name: "id", Value: movie.ID},
+		{Name: "title", Value: movie.Title},
+		{Name: "originalTitle", Value: movie.OriginalTitle},
+		{Name: "year", Value: movie.Year},
+		{Name: "sizeOnDisk", Value: render.Bytes(movie.Statistics.SizeOnDisk)},
+		{Name: "path", Value: movie.MovieFile.Path},
+		{Name: "added", Value: added},
+		{Name: "monitored", Value: movie.Monitored},
+		{Name: "hasFile", Value: movie.HasFile},
+		{Name: "genres", Value: movie.Genres},
+		{Name: "rating", Value: movie.Ratings.IMDb.Value},
+		{Name: "tmdbId", Value: movie.TMDBID},
+		{Name: "imdbId", Value: movie.IMDbID},
+	}
 }
 
-func DisplayMovies(movies []radarr.Movie, limit int, skip int) {
+// DisplayMovies sorts movies by size and prints a numbered page of them.
+func DisplayMovies(r *render.Renderer, instances []config.Instance, movies []Movie, limit int, skip int) error {
 	// Sort movies by SizeOnDisk in descending order
 	sort.Slice(movies, func(i, j int) bool {
 		return movies[i].SizeOnDisk > movies[j].SizeOnDisk
 	})
 
-	fmt.Println("Movies:")
 	// Iterate over the movies, starting from the skip index
+	var records []render.Record
 	for i := skip; i < len(movies) && i < skip+limit; i++ {
-		movie := movies[i]
-		fmt.Printf("%d: %s (%.2f GB)\n", i+1, movie.Title, float64(movie.SizeOnDisk)/(1024*1024*1024))
+		records = append(records, append(render.Record{{Name: "index", Value: i + 1}}, MovieRecord(movies[i])...))
 	}
+	r.Infof("Movies:\n")
+	return r.Render(records, withInstance(instances, "index", "title", "sizeOnDisk")...)
 }
 
 // GetUserSelections prompts the user to select movies to delete.
@@ -124,39 +197,46 @@ func ConfirmDeletion(movieTitle string, movieSize int64) bool {
 }
 
 // HandleSearchAndDelete manages the search and delete process.
-func HandleSearchAndDelete(radarrAPIKey, overseerAPIKey string, limit int, skip int) {
-	// Initialize and get configuration
-	config.InitConfig()
+func HandleSearchAndDelete(ctx context.Context, radarrAPIKey, overseerAPIKey, overseerCleanup string, limit int, skip int) error {
+	// Get configuration
 	conf := config.GetConfig()
-	if len(radarrAPIKey) > 0 {
-		conf.RadarrAPIKey = radarrAPIKey
+	instances, err := conf.Radarr(radarrAPIKey)
+	if err != nil {
+		return err
 	}
 	if len(overseerAPIKey) > 0 {
 		conf.OverseerAPIKey = overseerAPIKey
 	}
-	radarrClient := radarr.NewRadarrClient(conf.RadarrURL, conf.RadarrAPIKey)
-	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey)
-	fmt.Printf("Radarr API Endpoint %v\n", conf.RadarrURL)
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	// Overseer is loaded after the selection, so check the strategy first.
+	if _, err := cleanup.ParseStrategy(conf.OverseerCleanup); err != nil {
+		return err
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+	printEndpoints(r, instances)
 
-	// Fetch and display movies from Radarr
-	radarrMovies, err := radarrClient.GetMovies()
+	// Fetch and display movies from every selected Radarr instance
+	radarrMovies, err := FetchMovies(ctx, instances)
 	if err != nil {
-		fmt.Printf("Could not get movies: %v", err.Error())
+		return err
+	}
+	if err := DisplayMovies(r, instances, radarrMovies, limit, skip); err != nil {
+		return err
 	}
-	DisplayMovies(radarrMovies, limit, skip)
 
 	// Get user selections
 	selections, err := GetUserSelections(len(radarrMovies))
 	if err != nil {
-		fmt.Println(Red + err.Error() + Reset)
-		return
+		return err
 	}
 
-	// Fetch media items from Overseer
-	overseerMedia, err := overseerClient.GetMedia()
+	// Fetch media items and requests from Overseer
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
 	if err != nil {
-		fmt.Println(Red + err.Error() + Reset)
-		return
+		return err
 	}
 
 	// Process selections
@@ -164,25 +244,30 @@ func HandleSearchAndDelete(radarrAPIKey, overseerAPIKey string, limit int, skip
 		selectedMovie := radarrMovies[movieIndex-1]
 
 		if ConfirmDeletion(selectedMovie.Title, int64(selectedMovie.Statistics.SizeOnDisk)) {
-			// Delete media item from Overseer
-			if movieItem, err := FindMediaItemByTmdbID(selectedMovie.TMDBID, overseerMedia); err != nil {
-				fmt.Println(Red + err.Error() + Reset)
-			} else {
-				if err := overseerClient.DeleteMedia(movieItem.Id); err != nil {
-					fmt.Println(Red + err.Error() + Reset)
-				} else {
-					fmt.Printf(Green+"Request '%v' was successfully deleted from Overseer.\n"+Reset, selectedMovie.Title)
-				}
-			}
-
-			// Delete movie from Radarr
-			if err := radarrClient.DeleteMovie(selectedMovie.ID); err != nil {
-				fmt.Println(Red + err.Error() + Reset)
-			} else {
-				fmt.Printf(Green+"Movie '%v' was successfully deleted from Radarr.\n"+Reset, selectedMovie.Title)
-			}
+			DeleteMovie(ctx, overseerData, selectedMovie)
 		} else {
 			fmt.Printf("Skipped deletion of '%s'.\n", selectedMovie.Title)
 		}
 	}
+	return nil
+}
+
+// DeleteMovie removes a movie from Overseer (if it is known there) and from its Radarr instance.
+func DeleteMovie(ctx context.Context, overseerData *cleanup.Overseer, movie Movie) {
+	// Delete media item or requests from Overseer
+	if movieItem, err := FindMediaItemByTmdbID(movie.TMDBID, overseerData.Media); err != nil {
+		fmt.Println(Red + err.Error() + Reset)
+	} else {
+		overseerData.Remove(ctx, movie.Instance, *movieItem, movie.Title)
+	}
+
+	// Delete movie from Radarr
+	if err := movie.client.DeleteMovie(ctx, movie.ID); rest.IsNotFound(err) {
+		fmt.Printf(Yellow+"Movie '%v' was already removed from %s.\n"+Reset, movie.Title, radarrName(movie.Instance))
+	} else if err != nil {
+		fmt.Println(Red + err.Error() + Reset)
+	} else {
+		plan.Annotate(fmt.Sprintf("Delete movie '%s' from %s", movie.Title, radarrName(movie.Instance)), int64(movie.Statistics.SizeOnDisk))
+		fmt.Printf(Green+"Movie '%v' was successfully deleted from %s.\n"+Reset, movie.Title, radarrName(movie.Instance))
+	}
 }
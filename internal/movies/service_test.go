@@ -0,0 +1,41 @@
+package movies
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"slices"
+	"testing"
+)
+
+func TestDeleteMovie(t *testing.T) {
+	ctx := context.Background()
+	radarrURL, radarrCalls := rest.NewTestReplay(t, "testdata/delete-movie", "radarr")
+	overseerURL, overseerCalls := rest.NewTestReplay(t, "testdata/delete-movie", "overseer")
+
+	found, err := FetchMovies(ctx, []config.Instance{{Name: "main", URL: radarrURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("got %d movies, want 1", len(found))
+	}
+	overseerData, err := cleanup.Load(ctx, overseer.NewOverseerClient(overseerURL, "", rest.Options{}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	DeleteMovie(ctx, overseerData, found[0])
+
+	wantRadarr := []string{"GET /movie?excludeLocalCovers=false", "DELETE /movie/1?deleteFiles=true"}
+	if got := radarrCalls.Served(); !slices.Equal(got, wantRadarr) {
+		t.Errorf("Radarr calls = %q, want %q", got, wantRadarr)
+	}
+	// Nothing else refers to the media item, so it is deleted as a whole.
+	wantOverseer := []string{"GET /media?skip=0&sort=added&take=100", "GET /request?skip=0&take=100", "DELETE /media/5"}
+	if got := overseerCalls.Served(); !slices.Equal(got, wantOverseer) {
+		t.Errorf("Overseer calls = %q, want %q", got, wantOverseer)
+	}
+}
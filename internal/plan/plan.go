@@ -0,0 +1,84 @@
+package plan
+
+import (
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// maxBodyLength limits how much of a request body is shown when printing a plan.
+const maxBodyLength = 120
+
+// Step is a single mutating API call that was planned instead of executed.
+type Step struct {
+	Service     string
+	Method      string
+	Endpoint    string
+	Body        string
+	Description string
+	Bytes       int64
+}
+
+// Planner collects the steps that would have been executed during a dry run.
+type Planner struct {
+	Steps []Step
+}
+
+var active *Planner
+
+// Enable turns on dry-run mode. From then on clients record mutating calls instead of sending them.
+func Enable() *Planner {
+	active = &Planner{}
+	return active
+}
+
+// Active returns the current planner, or nil when dry-run mode is disabled.
+func Active() *Planner {
+	return active
+}
+
+// Record adds a planned call. The endpoint must already have its secrets redacted.
+func (p *Planner) Record(service, method, endpoint string, body []byte) {
+	p.Steps = append(p.Steps, Step{
+		Service:  service,
+		Method:   method,
+		Endpoint: endpoint,
+		Body:     string(body),
+	})
+}
+
+// Annotate attaches a description and the number of bytes it frees to the most recently
+// recorded step. It does nothing when dry-run mode is disabled.
+func Annotate(description string, bytes int64) {
+	if active == nil || len(active.Steps) == 0 {
+		return
+	}
+	step := &active.Steps[len(active.Steps)-1]
+	step.Description = description
+	step.Bytes += bytes
+}
+
+// Print writes an itemised plan and the total amount of space it would free.
+func (p *Planner) Print(w io.Writer) {
+	if len(p.Steps) == 0 {
+		fmt.Fprintln(w, "Dry run: no changes would be made.")
+		return
+	}
+
+	fmt.Fprintln(w, "Dry run: the following calls would be made:")
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+	fmt.Fprintf(tw, "#\tService\tMethod\tEndpoint\tDescription\tFrees (GB)\tBody\n")
+	fmt.Fprintf(tw, "-\t-------\t------\t--------\t-----------\t----------\t----\n")
+	var total int64
+	for i, step := range p.Steps {
+		total += step.Bytes
+		body := step.Body
+		if len(body) > maxBodyLength {
+			body = body[:maxBodyLength] + "..."
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%.2f\t%s\n", i+1, step.Service, step.Method, step.Endpoint, step.Description, units.ToGB(step.Bytes), body)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d calls planned, %.2f GB would be freed. Nothing was changed.\n", len(p.Steps), units.ToGB(total))
+}
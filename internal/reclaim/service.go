@@ -0,0 +1,279 @@
+package reclaim
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// Weights controls how much each signal contributes to a candidate's score.
+type Weights struct {
+	Size   float64
+	Age    float64
+	Rating float64
+}
+
+// Candidate is a single deletable item: a whole movie or one season of a series.
+type Candidate struct {
+	Kind     string // "movie" or "season"
+	Instance string
+	Title    string
+	Size     int64
+	Added    time.Time
+	Rating   float64 // 0-10, or -1 when unknown
+	Score    float64
+
+	movie        movies.Movie
+	series       series.Series
+	seasonNumber int
+}
+
+// movieCandidates turns Radarr movies with files on disk into candidates.
+func movieCandidates(radarrMovies []movies.Movie) []Candidate {
+	var candidates []Candidate
+	for _, movie := range radarrMovies {
+		if movie.Statistics.SizeOnDisk == 0 {
+			continue
+		}
+		added, _ := time.Parse(time.RFC3339, movie.Added)
+		rating := -1.0
+		if movie.Ratings.IMDb.Value > 0 {
+			rating = float64(movie.Ratings.IMDb.Value)
+		} else if movie.Ratings.TMDB.Value > 0 {
+			rating = float64(movie.Ratings.TMDB.Value)
+		}
+		candidates = append(candidates, Candidate{
+			Kind:     "movie",
+			Instance: movie.Instance.Name,
+			Title:    movie.Title,
+			Size:     int64(movie.Statistics.SizeOnDisk),
+			Added:    added,
+			Rating:   rating,
+			movie:    movie,
+		})
+	}
+	return candidates
+}
+
+// seasonCandidates turns every Sonarr season with files on disk into a candidate. Specials are
+// left out, and so are the airing seasons of ongoing series unless includeAiring is set.
+func seasonCandidates(sonarrSeries []series.Series, includeAiring bool) []Candidate {
+	var candidates []Candidate
+	for _, s := range sonarrSeries {
+		rating := -1.0
+		if s.Ratings.Value > 0 {
+			rating = float64(s.Ratings.Value)
+		}
+		airing := series.AiringSeasons(s.Series)
+		for _, season := range s.Seasons {
+			if season.Statistics.SizeOnDisk == 0 || season.SeasonNumber == 0 {
+				continue
+			}
+			if airing[season.SeasonNumber] && series.Ongoing(s.Series) && !includeAiring {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Kind:         "season",
+				Instance:     s.Instance.Name,
+				Title:        fmt.Sprintf("%s - Season %d", s.Title, season.SeasonNumber),
+				Size:         int64(season.Statistics.SizeOnDisk),
+				Added:        s.Added,
+				Rating:       rating,
+				series:       s,
+				seasonNumber: season.SeasonNumber,
+			})
+		}
+	}
+	return candidates
+}
+
+// score assigns each candidate a score between 0 and 1. Large, old and poorly rated
+// items score highest. Size is relative to the largest candidate and age is capped at ten years.
+func score(candidates []Candidate, weights Weights, now time.Time) {
+	var maxSize int64
+	for _, c := range candidates {
+		if c.Size > maxSize {
+			maxSize = c.Size
+		}
+	}
+	total := weights.Size + weights.Age + weights.Rating
+	if total <= 0 || maxSize == 0 {
+		return
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		sizeScore := float64(c.Size) / float64(maxSize)
+		ageScore := 0.0
+		if !c.Added.IsZero() {
+			ageScore = math.Min(now.Sub(c.Added).Hours()/24/3650, 1)
+		}
+		ratingScore := 0.5
+		if c.Rating >= 0 {
+			ratingScore = (10 - math.Min(c.Rating, 10)) / 10
+		}
+		c.Score = (weights.Size*sizeScore + weights.Age*ageScore + weights.Rating*ratingScore) / total
+	}
+}
+
+// BuildPlan ranks candidates by score and takes them in order until target bytes are reached.
+// The second return value reports whether the target can be met.
+func BuildPlan(candidates []Candidate, target int64, weights Weights, now time.Time) ([]Candidate, bool) {
+	score(candidates, weights, now)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	var plan []Candidate
+	var total int64
+	for _, c := range candidates {
+		if total >= target {
+			break
+		}
+		plan = append(plan, c)
+		total += c.Size
+	}
+	return plan, total >= target
+}
+
+// printPlan prints the plan with a running total of the space that will be freed.
+func printPlan(r *render.Renderer, plan []Candidate) error {
+	var records []render.Record
+	var cumulative int64
+	for i, c := range plan {
+		cumulative += c.Size
+		var rating any
+		if c.Rating >= 0 {
+			rating = c.Rating
+		}
+		records = append(records, render.Record{
+			{Name: "index", Value: i + 1},
+			{Name: "kind", Value: c.Kind},
+			{Name: "instance", Value: c.Instance},
+			{Name: "title", Value: c.Title},
+			{Name: "sizeOnDisk", Value: render.Bytes(c.Size)},
+			{Name: "added", Value: c.Added},
+			{Name: "rating", Value: rating},
+			{Name: "score", Value: c.Score},
+			{Name: "cumulative", Value: render.Bytes(cumulative)},
+		})
+	}
+	return r.Render(records)
+}
+
+// HandleReclaim builds a ranked deletion plan across Radarr and Sonarr that frees at least
+// target bytes, prints it and applies it after confirmation. A target that can't be reached
+// would delete everything, so that plan is only applied with allowPartial.
+func HandleReclaim(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup string, target int64, weights Weights, includeAiring, allowPartial, confirm bool) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(conf.RadarrInstances) == 0 && len(conf.SonarrInstances) == 0 {
+		return fmt.Errorf("no radarr or sonarr instance is configured; run \"fcli config init\"")
+	}
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	// Services without any configured or selected instance are left out, like in requests.LoadLibrary.
+	radarrInstances, sonarrInstances, err := conf.Both(radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	radarrMovies, err := movies.FetchMovies(ctx, radarrInstances)
+	if err != nil {
+		return err
+	}
+	sonarrSeries, err := series.FetchSeries(ctx, sonarrInstances)
+	if err != nil {
+		return err
+	}
+
+	candidates := append(movieCandidates(radarrMovies), seasonCandidates(sonarrSeries, includeAiring)...)
+	plan, reachable := BuildPlan(candidates, target, weights, time.Now())
+	if len(plan) == 0 {
+		r.Infof("Nothing to delete.\n")
+		return nil
+	}
+
+	if err := printPlan(r, plan); err != nil {
+		return err
+	}
+	var total int64
+	for _, c := range plan {
+		total += c.Size
+	}
+	if !reachable {
+		if !allowPartial {
+			return fmt.Errorf("target of %.2f GB cannot be reached; deleting everything frees only %.2f GB; lower --target or use --allow-partial to delete everything listed", units.ToGB(target), units.ToGB(total))
+		}
+		r.Infof(Yellow+"Target of %.2f GB cannot be reached; deleting everything frees only %.2f GB.\n"+Reset, units.ToGB(target), units.ToGB(total))
+	} else {
+		r.Infof("Plan frees %.2f GB (target %.2f GB) across %d items.\n", units.ToGB(total), units.ToGB(target), len(plan))
+	}
+
+	if !confirm {
+		fmt.Print(Yellow + "Apply this plan? (y/N): " + Reset)
+		reader := bufio.NewReader(os.Stdin)
+		input, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(input)) != "y" {
+			fmt.Println("Plan was not applied.")
+			return nil
+		}
+	}
+
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+	if err != nil {
+		return err
+	}
+	// Seasons are grouped per series so monitoring is updated in a single call. Series IDs
+	// are only unique within an instance.
+	type seriesKey struct {
+		instance string
+		id       int
+	}
+	var seriesOrder []seriesKey
+	seasonsBySeries := map[seriesKey][]Candidate{}
+	for _, c := range plan {
+		switch c.Kind {
+		case "movie":
+			movies.DeleteMovie(ctx, overseerData, c.movie)
+		case "season":
+			key := seriesKey{c.series.Instance.Name, c.series.ID}
+			if _, ok := seasonsBySeries[key]; !ok {
+				seriesOrder = append(seriesOrder, key)
+			}
+			seasonsBySeries[key] = append(seasonsBySeries[key], c)
+		}
+	}
+	for _, key := range seriesOrder {
+		var seasonNumbers []int
+		for _, c := range seasonsBySeries[key] {
+			seasonNumbers = append(seasonNumbers, c.seasonNumber)
+		}
+		series.DeleteSeasons(ctx, overseerData, seasonsBySeries[key][0].series, seasonNumbers)
+	}
+	return nil
+}
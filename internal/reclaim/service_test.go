@@ -0,0 +1,63 @@
+package reclaim
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBuildPlan(t *testing.T) {
+	ctx := context.Background()
+	radarrURL, _ := rest.NewTestReplay(t, "testdata", "radarr")
+	sonarrURL, _ := rest.NewTestReplay(t, "testdata", "sonarr")
+	radarrMovies, err := movies.FetchMovies(ctx, []config.Instance{{Name: "radarr", URL: radarrURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sonarrSeries, err := series.FetchSeries(ctx, []config.Instance{{Name: "sonarr", URL: sonarrURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		target        int64
+		weights       Weights
+		includeAiring bool
+		want          []string
+		wantReachable bool
+	}{
+		{"largest first", 60 * units.GB, Weights{Size: 1}, false, []string{"Big Old", "Long Procedural - Season 1"}, true},
+		{"oldest first", 50 * units.GB, Weights{Age: 1}, false, []string{"Long Procedural - Season 1", "Long Procedural - Season 2"}, true},
+		{"worst rated first", 80 * units.GB, Weights{Rating: 1}, false, []string{"Big Old", "Long Procedural - Season 1", "Long Procedural - Season 2"}, true},
+		{"airing season kept", 10 * units.GB, Weights{Size: 1}, false, []string{"Big Old"}, true},
+		{"airing season included", 10 * units.GB, Weights{Size: 1}, true, []string{"Airing Drama - Season 1"}, true},
+		{"unreachable", 200 * units.GB, Weights{Size: 1}, false, []string{"Big Old", "Long Procedural - Season 1", "Long Procedural - Season 2", "Small New"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Movies without files and specials are no candidates.
+			candidates := append(movieCandidates(radarrMovies), seasonCandidates(sonarrSeries, tt.includeAiring)...)
+
+			plan, reachable := BuildPlan(candidates, tt.target, tt.weights, now)
+
+			var got []string
+			for _, c := range plan {
+				got = append(got, c.Title)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("plan = %q, want %q", got, tt.want)
+			}
+			if reachable != tt.wantReachable {
+				t.Errorf("reachable = %v, want %v", reachable, tt.wantReachable)
+			}
+		})
+	}
+}
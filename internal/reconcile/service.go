@@ -0,0 +1,344 @@
+package reconcile
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/series"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// Fixes that can be applied to a finding.
+const (
+	// FixClear removes stale availability from Overseer.
+	FixClear = "clear"
+	// FixMarkAvailable marks a title that is on disk as available in Overseer.
+	FixMarkAvailable = "mark-available"
+)
+
+// Finding is a title Overseer and Radarr or Sonarr disagree about.
+type Finding struct {
+	// Side is where the orphan is: "overseer", "radarr" or "sonarr".
+	Side      string
+	Instance  string
+	Title     string
+	MediaType string
+	TmdbID    int
+	TvdbID    int
+	Media     *overseer.Media
+	Is4K      bool
+	Problem   string
+	Fix       string
+}
+
+// library is one kind (regular or 4K) of Radarr and Sonarr instances, indexed by the IDs Overseer uses.
+type library struct {
+	hasRadarr, hasSonarr bool
+	moviesByTmdb         map[int][]movies.Movie
+	seriesByTvdb         map[int][]series.Series
+	seriesByTmdb         map[int][]series.Series
+}
+
+func newLibrary(radarrInstances, sonarrInstances []config.Instance, radarrMovies []movies.Movie, sonarrSeries []series.Series, is4K bool) *library {
+	l := &library{
+		moviesByTmdb: map[int][]movies.Movie{},
+		seriesByTvdb: map[int][]series.Series{},
+		seriesByTmdb: map[int][]series.Series{},
+	}
+	for _, instance := range radarrInstances {
+		l.hasRadarr = l.hasRadarr || instance.Is4K == is4K
+	}
+	for _, instance := range sonarrInstances {
+		l.hasSonarr = l.hasSonarr || instance.Is4K == is4K
+	}
+	for _, m := range radarrMovies {
+		if m.Instance.Is4K == is4K {
+			l.moviesByTmdb[m.TMDBID] = append(l.moviesByTmdb[m.TMDBID], m)
+		}
+	}
+	for _, s := range sonarrSeries {
+		if s.Instance.Is4K == is4K {
+			l.seriesByTvdb[s.TvdbID] = append(l.seriesByTvdb[s.TvdbID], s)
+			l.seriesByTmdb[s.TmdbID] = append(l.seriesByTmdb[s.TmdbID], s)
+		}
+	}
+	return l
+}
+
+// onDisk reports whether the library holds files for a media item, and whether it knows the title at all.
+func (l *library) onDisk(media overseer.Media) (onDisk bool, known bool) {
+	if media.MediaType == "tv" {
+		candidates := l.seriesByTvdb[media.TvdbId]
+		if len(candidates) == 0 && media.TmdbId != 0 {
+			candidates = l.seriesByTmdb[media.TmdbId]
+		}
+		for _, s := range candidates {
+			if s.Statistics.EpisodeFileCount > 0 {
+				return true, true
+			}
+		}
+		return false, len(candidates) > 0
+	}
+	candidates := l.moviesByTmdb[media.TmdbId]
+	for _, m := range candidates {
+		if m.HasFile {
+			return true, true
+		}
+	}
+	return false, len(candidates) > 0
+}
+
+// Diff compares Overseer's media with the Radarr and Sonarr libraries in both directions.
+func Diff(media []overseer.Media, radarrInstances, sonarrInstances []config.Instance, radarrMovies []movies.Movie, sonarrSeries []series.Series) []Finding {
+	var findings []Finding
+	libraries := map[bool]*library{
+		false: newLibrary(radarrInstances, sonarrInstances, radarrMovies, sonarrSeries, false),
+		true:  newLibrary(radarrInstances, sonarrInstances, radarrMovies, sonarrSeries, true),
+	}
+	service := func(mediaType string) string {
+		if mediaType == "tv" {
+			return "Sonarr"
+		}
+		return "Radarr"
+	}
+
+	// Overseer titles that are available but gone from disk.
+	mediaByTmdb := map[int]*overseer.Media{}
+	mediaByTvdb := map[int]*overseer.Media{}
+	for i := range media {
+		m := &media[i]
+		if m.MediaType == "tv" {
+			if m.TvdbId != 0 {
+				mediaByTvdb[m.TvdbId] = m
+			}
+		} else {
+			mediaByTmdb[m.TmdbId] = m
+		}
+		for _, is4K := range []bool{false, true} {
+			status := m.Status
+			if is4K {
+				status = m.Status4k
+			}
+			l := libraries[is4K]
+			if status < overseer.MediaStatusPartiallyAvailable || (m.MediaType == "tv" && !l.hasSonarr) || (m.MediaType != "tv" && !l.hasRadarr) {
+				continue
+			}
+			onDisk, known := l.onDisk(*m)
+			if onDisk {
+				continue
+			}
+			problem := fmt.Sprintf("%s in Overseer but not in %s", overseer.MediaStatusName(status), service(m.MediaType))
+			if known {
+				problem = fmt.Sprintf("%s in Overseer but %s has no files", overseer.MediaStatusName(status), service(m.MediaType))
+			}
+			findings = append(findings, Finding{Side: "overseer", Title: m.Title, MediaType: m.MediaType, TmdbID: m.TmdbId, TvdbID: m.TvdbId, Media: m, Is4K: is4K, Problem: problem, Fix: FixClear})
+		}
+	}
+
+	// Radarr and Sonarr titles on disk that Overseer doesn't know or doesn't show as available.
+	seen := map[string]bool{}
+	check := func(side, instance, title, mediaType string, tmdbID, tvdbID int, m *overseer.Media, is4K bool) {
+		key := fmt.Sprintf("%s/%d/%d/%t", mediaType, tmdbID, tvdbID, is4K)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if m == nil {
+			findings = append(findings, Finding{Side: side, Instance: instance, Title: title, MediaType: mediaType, TmdbID: tmdbID, TvdbID: tvdbID, Is4K: is4K, Problem: "not in Overseer"})
+			return
+		}
+		status := m.Status
+		if is4K {
+			status = m.Status4k
+		}
+		wanted := overseer.MediaStatusAvailable
+		if mediaType == "tv" {
+			wanted = overseer.MediaStatusPartiallyAvailable
+		}
+		if status < wanted {
+			findings = append(findings, Finding{Side: side, Instance: instance, Title: title, MediaType: mediaType, TmdbID: tmdbID, TvdbID: tvdbID, Media: m, Is4K: is4K, Problem: fmt.Sprintf("on disk but %s in Overseer", overseer.MediaStatusName(status)), Fix: FixMarkAvailable})
+		}
+	}
+	for _, movie := range radarrMovies {
+		if movie.HasFile {
+			check("radarr", movie.Instance.Name, movie.Title, "movie", movie.TMDBID, 0, mediaByTmdb[movie.TMDBID], movie.Instance.Is4K)
+		}
+	}
+	for _, s := range sonarrSeries {
+		if s.Statistics.EpisodeFileCount > 0 {
+			check("sonarr", s.Instance.Name, s.Title, "tv", s.TmdbID, s.TvdbID, mediaByTvdb[s.TvdbID], s.Instance.Is4K)
+		}
+	}
+
+	// Overseer doesn't always return titles, so name stale media after what the libraries call them.
+	for i := range findings {
+		if findings[i].Title == "" {
+			findings[i].Title = fmt.Sprintf("tmdb:%d", findings[i].TmdbID)
+		}
+	}
+	return findings
+}
+
+// HandleReconcile reports titles Overseer and Radarr/Sonarr disagree about and offers to fix them.
+// clearStale and markAvailable apply the fixes without asking; otherwise tables ask for each fix.
+func HandleReconcile(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey string, clearStale, markAvailable bool) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	radarrInstances, sonarrInstances, err := conf.Both(radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	radarrMovies, err := movies.FetchMovies(ctx, radarrInstances)
+	if err != nil {
+		return err
+	}
+	sonarrSeries, err := series.FetchSeries(ctx, sonarrInstances)
+	if err != nil {
+		return err
+	}
+	media, err := overseerClient.GetMedia(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Name Overseer media after the library, which knows titles Overseer's media list leaves out.
+	titles := map[string]string{}
+	for _, m := range radarrMovies {
+		titles[fmt.Sprintf("movie/%d", m.TMDBID)] = m.Title
+	}
+	for _, s := range sonarrSeries {
+		titles[fmt.Sprintf("tv/%d", s.TmdbID)] = s.Title
+	}
+	for i := range media {
+		if media[i].Title == "" {
+			media[i].Title = titles[fmt.Sprintf("%s/%d", media[i].MediaType, media[i].TmdbId)]
+		}
+	}
+
+	findings := Diff(media, radarrInstances, sonarrInstances, radarrMovies, sonarrSeries)
+	if len(findings) == 0 {
+		r.Infof(Green + "Overseer and the libraries agree.\n" + Reset)
+		return nil
+	}
+
+	var records []render.Record
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[f.Fix]++
+		var mediaID any
+		if f.Media != nil {
+			mediaID = f.Media.Id
+		}
+		records = append(records, render.Record{
+			{Name: "side", Value: f.Side},
+			{Name: "instance", Value: f.Instance},
+			{Name: "title", Value: f.Title},
+			{Name: "mediaType", Value: f.MediaType},
+			{Name: "is4k", Value: f.Is4K},
+			{Name: "mediaId", Value: mediaID},
+			{Name: "tmdbId", Value: f.TmdbID},
+			{Name: "tvdbId", Value: f.TvdbID},
+			{Name: "problem", Value: f.Problem},
+			{Name: "fix", Value: f.Fix},
+		})
+	}
+	if err := r.Render(records, "side", "title", "mediaType", "is4k", "problem", "fix"); err != nil {
+		return err
+	}
+	r.Infof("%d stale in Overseer, %d not shown as available, %d unknown to Overseer (added on its next library scan).\n", counts[FixClear], counts[FixMarkAvailable], counts[""])
+
+	reader := bufio.NewReader(os.Stdin)
+	ask := func(fix string, flag bool, question string) bool {
+		if counts[fix] == 0 {
+			return false
+		}
+		if flag {
+			return true
+		}
+		// Prompts would corrupt machine-readable output.
+		if !r.IsTable() {
+			return false
+		}
+		fmt.Printf(Yellow+question+" (y/N): "+Reset, counts[fix])
+		input, _ := reader.ReadString('\n')
+		return strings.ToLower(strings.TrimSpace(input)) == "y"
+	}
+	clear := ask(FixClear, clearStale, "Clear %d stale Overseer media items?")
+	mark := ask(FixMarkAvailable, markAvailable, "Mark %d titles as available in Overseer?")
+
+	failed := 0
+	for _, f := range findings {
+		var err error
+		switch {
+		case f.Fix == FixClear && clear:
+			err = clearMedia(ctx, overseerClient, f)
+		case f.Fix == FixMarkAvailable && mark:
+			err = markMedia(ctx, overseerClient, f)
+		default:
+			continue
+		}
+		if rest.IsNotFound(err) {
+			fmt.Printf(Yellow+"'%s' was already removed from Overseer.\n"+Reset, f.Title)
+		} else if err != nil {
+			failed++
+			fmt.Println(Red + err.Error() + Reset)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d fixes could not be applied", failed)
+	}
+	return nil
+}
+
+// clearMedia removes stale availability. The media item is deleted unless the other (regular or
+// 4K) server still has the title, in which case only this server's status is reset.
+func clearMedia(ctx context.Context, client *overseer.OverseerClient, f Finding) error {
+	other := f.Media.Status4k
+	if f.Is4K {
+		other = f.Media.Status
+	}
+	if other > overseer.MediaStatusUnknown {
+		if err := client.SetMediaStatus(ctx, f.Media.Id, "unknown", f.Is4K); err != nil {
+			return err
+		}
+		plan.Annotate(fmt.Sprintf("Reset Overseer status of '%s'", f.Title), 0)
+		fmt.Printf(Green+"Overseer status of '%s' was reset.\n"+Reset, f.Title)
+		return nil
+	}
+	if err := client.DeleteMedia(ctx, f.Media.Id); err != nil {
+		return err
+	}
+	plan.Annotate(fmt.Sprintf("Delete stale Overseer media for '%s'", f.Title), 0)
+	fmt.Printf(Green+"Stale Overseer media for '%s' was cleared.\n"+Reset, f.Title)
+	return nil
+}
+
+// markMedia marks a title that is on disk as available.
+func markMedia(ctx context.Context, client *overseer.OverseerClient, f Finding) error {
+	if err := client.SetMediaStatus(ctx, f.Media.Id, "available", f.Is4K); err != nil {
+		return err
+	}
+	plan.Annotate(fmt.Sprintf("Mark '%s' as available in Overseer", f.Title), 0)
+	fmt.Printf(Green+"'%s' was marked as available in Overseer.\n"+Reset, f.Title)
+	return nil
+}
@@ -0,0 +1,309 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats.
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+	CSV   = "csv"
+	TSV   = "tsv"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{Table, JSON, YAML, CSV, TSV}
+
+// Bytes is a size in bytes. Tables show it in GB, every other format shows the raw number.
+type Bytes int64
+
+// Display formats the size for tables.
+func (b Bytes) Display() string {
+	return fmt.Sprintf("%.2f GB", float64(b)/(1024*1024*1024))
+}
+
+// displayer is implemented by values that have a human friendly representation for tables.
+type displayer interface {
+	Display() string
+}
+
+// Field is a single named value of a record. Names are stable and match the *arr/Overseerr
+// JSON models so they can be used in scripts and with --columns.
+type Field struct {
+	Name  string
+	Value any
+}
+
+// Record is an ordered list of fields describing one listed item.
+type Record []Field
+
+// Get returns the value of a field and whether the record has it.
+func (r Record) Get(name string) (any, bool) {
+	for _, f := range r {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// MarshalJSON encodes the record as an object, keeping field order.
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML encodes the record as a mapping, keeping field order.
+func (r Record) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range r {
+		value := &yaml.Node{}
+		if err := value.Encode(f.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.Name}, value)
+	}
+	return node, nil
+}
+
+// Renderer writes records in the selected output format.
+type Renderer struct {
+	Out     io.Writer
+	Format  string
+	Columns []string
+}
+
+// New creates a Renderer writing to stdout.
+func New(format string, columns []string) *Renderer {
+	if format == "" {
+		format = Table
+	}
+	return &Renderer{
+		Out:     os.Stdout,
+		Format:  strings.ToLower(format),
+		Columns: columns,
+	}
+}
+
+// IsTable reports whether output is meant for humans rather than other programs.
+func (r *Renderer) IsTable() bool {
+	return r.Format == Table
+}
+
+// Infof prints a status message. It goes to stdout for tables and to stderr for
+// machine-readable formats so it doesn't corrupt the output.
+func (r *Renderer) Infof(format string, args ...any) {
+	out := r.Out
+	if !r.IsTable() {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, format, args...)
+}
+
+// Render writes the records. When no columns were selected, tables show defaultColumns
+// (or every field if none are given) and the other formats show every field.
+func (r *Renderer) Render(records []Record, defaultColumns ...string) error {
+	columns := r.Columns
+	if len(columns) == 0 && r.IsTable() {
+		columns = defaultColumns
+	}
+	records, err := selectColumns(records, columns)
+	if err != nil {
+		return err
+	}
+
+	switch r.Format {
+	case Table:
+		return r.renderTable(records)
+	case JSON:
+		if records == nil {
+			records = []Record{}
+		}
+		encoder := json.NewEncoder(r.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case YAML:
+		encoder := yaml.NewEncoder(r.Out)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(records)
+	case CSV, TSV:
+		return r.renderDelimited(records)
+	default:
+		return fmt.Errorf("unknown output format %q, expected one of %s", r.Format, strings.Join(Formats, ", "))
+	}
+}
+
+// selectColumns reorders and filters the fields of every record. Unknown columns are an error.
+func selectColumns(records []Record, columns []string) ([]Record, error) {
+	if len(columns) == 0 || len(records) == 0 {
+		return records, nil
+	}
+	for _, column := range columns {
+		if _, ok := records[0].Get(column); !ok {
+			var available []string
+			for _, f := range records[0] {
+				available = append(available, f.Name)
+			}
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", column, strings.Join(available, ","))
+		}
+	}
+
+	selected := make([]Record, 0, len(records))
+	for _, record := range records {
+		var fields Record
+		for _, column := range columns {
+			for _, f := range record {
+				if strings.EqualFold(f.Name, column) {
+					fields = append(fields, f)
+					break
+				}
+			}
+		}
+		selected = append(selected, fields)
+	}
+	return selected, nil
+}
+
+// header turns a field name like "sizeOnDisk" into a table header like "SIZE ON DISK".
+func header(name string) string {
+	if name == "index" {
+		return "#"
+	}
+	var b strings.Builder
+	runes := []rune(name)
+	for i, c := range runes {
+		if i > 0 && unicode.IsUpper(c) && !unicode.IsUpper(runes[i-1]) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
+
+// tableValue formats a value for human readable tables.
+func tableValue(value any) string {
+	switch v := value.(type) {
+	case displayer:
+		return v.Display()
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Format("2006-01-02")
+	case float32, float64:
+		return fmt.Sprintf("%.2f", v)
+	case []string:
+		return strings.Join(v, ", ")
+	case nil:
+		return "-"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// rawValue formats a value for delimited output.
+func rawValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	case []Record:
+		// Nested records, like the seasons of a series, are kept as JSON in a single cell.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (r *Renderer) renderTable(records []Record) error {
+	if len(records) == 0 {
+		fmt.Fprintln(r.Out, "No results.")
+		return nil
+	}
+	w := tabwriter.NewWriter(r.Out, 1, 1, 2, ' ', 0)
+	var headers, separators []string
+	for _, f := range records[0] {
+		h := header(f.Name)
+		headers = append(headers, h)
+		separators = append(separators, strings.Repeat("-", len(h)))
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	fmt.Fprintln(w, strings.Join(separators, "\t"))
+	for _, record := range records {
+		var values []string
+		for _, f := range record {
+			values = append(values, tableValue(f.Value))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
+
+func (r *Renderer) renderDelimited(records []Record) error {
+	w := csv.NewWriter(r.Out)
+	if r.Format == TSV {
+		w.Comma = '\t'
+	}
+	if len(records) > 0 {
+		var headers []string
+		for _, f := range records[0] {
+			headers = append(headers, f.Name)
+		}
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		var values []string
+		for _, f := range record {
+			values = append(values, rawValue(f.Value))
+		}
+		if err := w.Write(values); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
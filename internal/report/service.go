@@ -0,0 +1,85 @@
+package report
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/requests"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// HandleRequesters reports how much disk space the requests of every Overseer user take up.
+// An item requested by several users counts in full for each of them.
+func HandleRequesters(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey string, top int) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	allRequests, err := overseerClient.GetRequests(ctx)
+	if err != nil {
+		return err
+	}
+	library, err := requests.LoadLibrary(ctx, conf, radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	order := requests.UsageByUser(allRequests, library)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].Size > order[j].Size
+	})
+
+	var records []render.Record
+	var total int64
+	for _, u := range order {
+		total += u.Size
+		sort.SliceStable(u.Items, func(i, j int) bool {
+			return u.Items[i].Size > u.Items[j].Size
+		})
+		var largest []string
+		for i, item := range u.Items {
+			if i == top {
+				break
+			}
+			largest = append(largest, fmt.Sprintf("%s (%.2f GB)", item.Title, units.ToGB(item.Size)))
+		}
+		var oldest, newest time.Time
+		// Ages are summed in days, since a time.Duration overflows at 292 years in total.
+		var totalAgeDays float64
+		for _, request := range u.Requests {
+			totalAgeDays += float64(now.Sub(request.CreatedAt)) / float64(units.Day)
+			if oldest.IsZero() || request.CreatedAt.Before(oldest) {
+				oldest = request.CreatedAt
+			}
+			if request.CreatedAt.After(newest) {
+				newest = request.CreatedAt
+			}
+		}
+		records = append(records, render.Record{
+			{Name: "user", Value: u.User.Name()},
+			{Name: "userId", Value: u.User.ID},
+			{Name: "requests", Value: len(u.Requests)},
+			{Name: "items", Value: len(u.Items)},
+			{Name: "unresolved", Value: u.Unresolved},
+			{Name: "sizeOnDisk", Value: render.Bytes(u.Size)},
+			{Name: "oldestRequest", Value: oldest},
+			{Name: "newestRequest", Value: newest},
+			{Name: "avgAgeDays", Value: int(totalAgeDays / float64(len(u.Requests)))},
+			{Name: "largestItems", Value: largest},
+		})
+	}
+	if err := r.Render(records, "user", "requests", "items", "sizeOnDisk", "oldestRequest", "avgAgeDays", "largestItems"); err != nil {
+		return err
+	}
+	r.Infof("%d users, %.2f GB requested in total.\n", len(order), units.ToGB(total))
+	return nil
+}
@@ -0,0 +1,265 @@
+package requests
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for expiring requests when expiry.tag and expiry.ttl aren't configured.
+const (
+	DefaultExpiryTag = "expire"
+	DefaultExpiryTTL = "30d"
+)
+
+// ExpiryPolicy reads expiry tags. A title tagged Tag expires TTL after it became available;
+// a title tagged "<Tag>-<age>", e.g. expire-7d, expires after that age.
+type ExpiryPolicy struct {
+	Tag string
+	TTL time.Duration
+}
+
+// NewExpiryPolicy builds the policy from the configuration, falling back to the defaults.
+func NewExpiryPolicy(conf *config.Configuration) (ExpiryPolicy, error) {
+	policy := ExpiryPolicy{Tag: conf.ExpiryTag}
+	if policy.Tag == "" {
+		policy.Tag = DefaultExpiryTag
+	}
+	ttl := conf.ExpiryTTL
+	if ttl == "" {
+		ttl = DefaultExpiryTTL
+	}
+	var err error
+	if policy.TTL, err = units.ParseAge(ttl); err != nil {
+		return policy, fmt.Errorf("invalid expiry.ttl: %w", err)
+	}
+	return policy, nil
+}
+
+// TTLOf returns the time to live an expiry tag label sets.
+func (p ExpiryPolicy) TTLOf(label string) (time.Duration, bool) {
+	label, tag := strings.ToLower(label), strings.ToLower(p.Tag)
+	if label == tag {
+		return p.TTL, true
+	}
+	if age, ok := strings.CutPrefix(label, tag+"-"); ok {
+		if ttl, err := units.ParseAge(age); err == nil {
+			return ttl, true
+		}
+	}
+	return 0, false
+}
+
+// Expiry is a title tagged for expiry, directly or through one of its requests.
+type Expiry struct {
+	Item     Item
+	Requests []overseer.Request
+	Tag      string
+	TTL      time.Duration
+	// Available is when the title became available; it expires TTL later.
+	Available time.Time
+	ExpiresAt time.Time
+}
+
+// Due reports whether the title has been available for longer than its TTL.
+func (e Expiry) Due(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+// Expiring finds the titles on disk with an expiry tag on the title itself or on a request for
+// it. Tag IDs are local to an instance, so labels are looked up per instance. When several
+// expiry tags apply, the shortest TTL wins.
+func Expiring(ctx context.Context, policy ExpiryPolicy, library *Library, overseerData *cleanup.Overseer) ([]Expiry, error) {
+	requestsByKey := map[string][]overseer.Request{}
+	for _, request := range overseerData.Requests {
+		if item, ok := library.Resolve(request); ok {
+			requestsByKey[item.Key()] = append(requestsByKey[item.Key()], request)
+		}
+	}
+	mediaByTmdb := map[int]overseer.Media{}
+	mediaByTvdb := map[int]overseer.Media{}
+	for _, media := range overseerData.Media {
+		if media.MediaType == "tv" {
+			mediaByTvdb[media.TvdbId] = media
+		} else {
+			mediaByTmdb[media.TmdbId] = media
+		}
+	}
+
+	labels := map[string]map[int]string{}
+	var expiring []Expiry
+	check := func(item Item, tags []int, added time.Time, media overseer.Media, service string, fetch func() (map[int]string, error)) error {
+		key := service + "/" + item.Instance().Name
+		if _, ok := labels[key]; !ok {
+			l, err := fetch()
+			if err != nil {
+				return err
+			}
+			labels[key] = l
+		}
+		requests := requestsByKey[item.Key()]
+		for _, request := range requests {
+			tags = append(tags, request.Tags...)
+		}
+
+		e := Expiry{Item: item, Requests: requests}
+		for _, id := range tags {
+			label := labels[key][id]
+			if ttl, ok := policy.TTLOf(label); ok && (e.Tag == "" || ttl < e.TTL) {
+				e.Tag, e.TTL = label, ttl
+			}
+		}
+		if e.Tag == "" {
+			return nil
+		}
+		// Overseer knows when the title became available; Radarr and Sonarr only when it was added.
+		e.Available = media.MediaAddedAt
+		if e.Available.IsZero() {
+			e.Available = added
+		}
+		e.ExpiresAt = e.Available.Add(e.TTL)
+		expiring = append(expiring, e)
+		return nil
+	}
+
+	for i := range library.Movies {
+		m := library.Movies[i]
+		if !m.HasFile {
+			continue
+		}
+		added, err := time.Parse(time.RFC3339, m.MovieFile.DateAdded)
+		if err != nil {
+			added, _ = time.Parse(time.RFC3339, m.Added)
+		}
+		item := Item{Title: m.Title, Size: int64(m.Statistics.SizeOnDisk), Movie: &m}
+		if err := check(item, m.Tags, added, mediaByTmdb[m.TMDBID], "radarr", func() (map[int]string, error) { return m.TagLabels(ctx) }); err != nil {
+			return nil, err
+		}
+	}
+	for i := range library.Series {
+		s := library.Series[i]
+		if s.Statistics.EpisodeFileCount == 0 {
+			continue
+		}
+		item := Item{Title: s.Title, Size: int64(s.Statistics.SizeOnDisk), Series: &s}
+		if err := check(item, s.Tags, s.Added, mediaByTvdb[s.TvdbID], "sonarr", func() (map[int]string, error) { return s.TagLabels(ctx) }); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].ExpiresAt.Before(expiring[j].ExpiresAt)
+	})
+	return expiring, nil
+}
+
+// formatDays formats a duration in whole days, e.g. "30d".
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%dd", int(math.Ceil(d.Hours()/24)))
+}
+
+// HandleExpiring lists the titles tagged for expiry with the date they are due. With del the due
+// titles are deleted through the regular Radarr, Sonarr and Overseer cleanup, after one
+// confirmation (or confirm).
+func HandleExpiring(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup string, del, confirm bool) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+	policy, err := NewExpiryPolicy(conf)
+	if err != nil {
+		return err
+	}
+
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+	if err != nil {
+		return err
+	}
+	library, err := LoadLibrary(ctx, conf, radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	expiring, err := Expiring(ctx, policy, library, overseerData)
+	if err != nil {
+		return err
+	}
+	if len(expiring) == 0 {
+		r.Infof("No titles are tagged %q or %q.\n", policy.Tag, policy.Tag+"-<age>")
+		return nil
+	}
+
+	now := time.Now()
+	var due []Expiry
+	var total int64
+	var records []render.Record
+	for _, e := range expiring {
+		status := "in " + formatDays(e.ExpiresAt.Sub(now))
+		if e.Due(now) {
+			status = "due"
+			due = append(due, e)
+			total += e.Item.Size
+		}
+		var requestedBy []string
+		for _, request := range e.Requests {
+			requestedBy = append(requestedBy, request.RequestedBy.Name())
+		}
+		mediaType := "movie"
+		if e.Item.Series != nil {
+			mediaType = "tv"
+		}
+		records = append(records, render.Record{
+			{Name: "title", Value: e.Item.Title},
+			{Name: "type", Value: mediaType},
+			{Name: "instance", Value: e.Item.Instance().Name},
+			{Name: "tag", Value: e.Tag},
+			{Name: "ttl", Value: formatDays(e.TTL)},
+			{Name: "available", Value: e.Available},
+			{Name: "expiresAt", Value: e.ExpiresAt},
+			{Name: "due", Value: status},
+			{Name: "requestedBy", Value: unique(requestedBy)},
+			{Name: "sizeOnDisk", Value: render.Bytes(e.Item.Size)},
+		})
+	}
+	if err := r.Render(records, "title", "type", "tag", "expiresAt", "due", "requestedBy", "sizeOnDisk"); err != nil {
+		return err
+	}
+	r.Infof("%d of %d expiring titles are due, %.2f GB can be freed.\n", len(due), len(expiring), units.ToGB(total))
+
+	if !del || len(due) == 0 {
+		return nil
+	}
+	if !confirm {
+		fmt.Printf(Yellow+"Delete the %d due titles? (y/N): "+Reset, len(due))
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(input)) != "y" {
+			fmt.Println("Nothing was deleted.")
+			return nil
+		}
+	}
+	for _, e := range due {
+		if e.Item.Movie != nil {
+			movies.DeleteMovie(ctx, overseerData, *e.Item.Movie)
+		} else {
+			series.DeleteSeries(ctx, overseerData, *e.Item.Series)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,511 @@
+package requests
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/radarr"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GateOptions control what HandleGate does with the pending requests.
+type GateOptions struct {
+	// MinFree is the space to keep free on every disk, e.g. "100GB"; it overrides gate.minFree.
+	MinFree string
+	// Decline declines the requests that don't fit instead of leaving them pending.
+	Decline bool
+	// Reason is printed for declined requests instead of the default naming the missing space.
+	// Overseer has no way to pass it on to the users.
+	Reason  string
+	Confirm bool
+	// Every repeats the gate at this interval until fcli is interrupted; zero runs it once.
+	Every time.Duration
+}
+
+// profile is a quality profile with the size limit of its largest allowed quality.
+type profile struct {
+	name        string
+	mbPerMinute float64
+}
+
+// disk is the free space of one disk, less what the current run approved onto it.
+type disk struct {
+	path string
+	free int64
+}
+
+// destination is a Radarr or Sonarr instance as the gate sees it.
+type destination struct {
+	service  string
+	instance config.Instance
+	profiles map[int]profile
+	// folders maps root folder paths and IDs to the disk they are on.
+	folders map[string]*disk
+	paths   []string
+}
+
+// sizeLimit is the most a quality takes per minute. Without a maximum, the preferred size is used.
+func sizeLimit(minSize, maxSize, preferredSize float64) float64 {
+	if maxSize > 0 {
+		return maxSize
+	}
+	if preferredSize > 0 {
+		return preferredSize
+	}
+	return minSize
+}
+
+// within reports whether path is dir or lies below it.
+func within(path, dir string) bool {
+	path, dir = strings.TrimRight(path, "/"), strings.TrimRight(dir, "/")
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// rootFolder is a root folder with the free space Radarr or Sonarr reports for it.
+type rootFolder struct {
+	id   int
+	path string
+	free int64
+}
+
+// settings is what the gate needs from a Radarr or Sonarr instance, in the same shape for both.
+type settings struct {
+	// profiles names the quality profiles and allowed lists the qualities each allows, by ID.
+	profiles map[int]string
+	allowed  map[int][]int
+	// limits is the size limit of every quality in MB per minute.
+	limits  map[int]float64
+	folders []rootFolder
+	disks   []*disk
+}
+
+// gateClient is the part of a Radarr or Sonarr client the gate uses. Both APIs return the same
+// data, each in its own types.
+type gateClient[P, D, F, S any] interface {
+	GetQualityProfiles(ctx context.Context) ([]P, error)
+	GetQualityDefinitions(ctx context.Context) ([]D, error)
+	GetRootFolders(ctx context.Context) ([]F, error)
+	GetDiskSpace(ctx context.Context) ([]S, error)
+}
+
+// readers turn the responses of a gateClient into settings.
+type readers[P, D, F, S any] struct {
+	profile    func(P) (id int, name string, allowed []int)
+	definition func(D) (quality int, limit float64)
+	folder     func(F) rootFolder
+	disk       func(S) *disk
+}
+
+// fetchSettings fetches the settings the gate needs from a Radarr or Sonarr instance.
+func fetchSettings[P, D, F, S any](ctx context.Context, client gateClient[P, D, F, S], read readers[P, D, F, S]) (settings, error) {
+	s := settings{profiles: map[int]string{}, allowed: map[int][]int{}, limits: map[int]float64{}}
+	profiles, err := client.GetQualityProfiles(ctx)
+	if err != nil {
+		return s, err
+	}
+	definitions, err := client.GetQualityDefinitions(ctx)
+	if err != nil {
+		return s, err
+	}
+	folders, err := client.GetRootFolders(ctx)
+	if err != nil {
+		return s, err
+	}
+	space, err := client.GetDiskSpace(ctx)
+	if err != nil {
+		return s, err
+	}
+	for _, p := range profiles {
+		id, name, allowed := read.profile(p)
+		s.profiles[id] = name
+		s.allowed[id] = allowed
+	}
+	for _, definition := range definitions {
+		quality, limit := read.definition(definition)
+		s.limits[quality] = limit
+	}
+	for _, folder := range folders {
+		s.folders = append(s.folders, read.folder(folder))
+	}
+	for _, d := range space {
+		s.disks = append(s.disks, read.disk(d))
+	}
+	return s, nil
+}
+
+// fetchRadarr fetches the settings the gate needs from a Radarr instance.
+func fetchRadarr(ctx context.Context, instance config.Instance) (settings, error) {
+	client := radarr.NewRadarrClient(instance.URL, instance.APIKey, instance.Options)
+	return fetchSettings(ctx, client, readers[radarr.QualityProfile, radarr.QualityDefinition, radarr.RootFolder, radarr.DiskSpace]{
+		profile: func(p radarr.QualityProfile) (int, string, []int) { return p.ID, p.Name, p.AllowedQualities() },
+		definition: func(d radarr.QualityDefinition) (int, float64) {
+			return d.Quality.ID, sizeLimit(d.MinSize, d.MaxSize, d.PreferredSize)
+		},
+		folder: func(f radarr.RootFolder) rootFolder { return rootFolder{f.ID, f.Path, f.FreeSpace} },
+		disk:   func(d radarr.DiskSpace) *disk { return &disk{path: d.Path, free: d.FreeSpace} },
+	})
+}
+
+// fetchSonarr fetches the settings the gate needs from a Sonarr instance.
+func fetchSonarr(ctx context.Context, instance config.Instance) (settings, error) {
+	client := sonarr.NewSonarrClient(instance.URL, instance.APIKey, instance.Options)
+	return fetchSettings(ctx, client, readers[sonarr.QualityProfile, sonarr.QualityDefinition, sonarr.RootFolder, sonarr.DiskSpace]{
+		profile: func(p sonarr.QualityProfile) (int, string, []int) { return p.ID, p.Name, p.AllowedQualities() },
+		definition: func(d sonarr.QualityDefinition) (int, float64) {
+			return d.Quality.ID, sizeLimit(d.MinSize, d.MaxSize, d.PreferredSize)
+		},
+		folder: func(f sonarr.RootFolder) rootFolder { return rootFolder{f.ID, f.Path, f.FreeSpace} },
+		disk:   func(d sonarr.DiskSpace) *disk { return &disk{path: d.Path, free: d.FreeSpace} },
+	})
+}
+
+// newDestination rates every quality profile by the largest size limit of its allowed qualities
+// and places every root folder on the disk with the longest path containing it. Root folders
+// outside every reported disk keep the free space reported for the folder itself.
+func newDestination(service string, instance config.Instance, s settings) *destination {
+	d := &destination{service: service, instance: instance, profiles: map[int]profile{}, folders: map[string]*disk{}}
+	for id, name := range s.profiles {
+		p := profile{name: name}
+		for _, quality := range s.allowed[id] {
+			p.mbPerMinute = max(p.mbPerMinute, s.limits[quality])
+		}
+		d.profiles[id] = p
+	}
+	for _, folder := range s.folders {
+		var on *disk
+		for _, dk := range s.disks {
+			if within(folder.path, dk.path) && (on == nil || len(dk.path) > len(on.path)) {
+				on = dk
+			}
+		}
+		if on == nil {
+			on = &disk{path: folder.path, free: folder.free}
+		}
+		d.folders[strings.TrimRight(folder.path, "/")] = on
+		d.folders[strconv.Itoa(folder.id)] = on
+		d.paths = append(d.paths, folder.path)
+	}
+	return d
+}
+
+// loadDestinations loads every selected instance. Services without any
+// configured or selected instance are left out.
+func loadDestinations(ctx context.Context, conf *config.Configuration, radarrAPIKey, sonarrAPIKey string) ([]*destination, error) {
+	radarrInstances, sonarrInstances, err := conf.Both(radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	var destinations []*destination
+	for _, instance := range radarrInstances {
+		s, err := fetchRadarr(ctx, instance)
+		if err != nil {
+			return nil, fmt.Errorf("radarr (%s): %w", instance.Name, err)
+		}
+		destinations = append(destinations, newDestination("radarr", instance, s))
+	}
+	for _, instance := range sonarrInstances {
+		s, err := fetchSonarr(ctx, instance)
+		if err != nil {
+			return nil, fmt.Errorf("sonarr (%s): %w", instance.Name, err)
+		}
+		destinations = append(destinations, newDestination("sonarr", instance, s))
+	}
+	return destinations, nil
+}
+
+// profile returns the quality profile a request downloads with: its own, else the instance's
+// default, else the only one there is.
+func (d *destination) profile(request overseer.Request) (profile, error) {
+	if p, ok := d.profiles[request.ProfileID]; ok {
+		return p, nil
+	}
+	wanted := d.instance.QualityProfile
+	for id, p := range d.profiles {
+		if wanted != "" && (strings.EqualFold(p.name, wanted) || strconv.Itoa(id) == wanted) {
+			return p, nil
+		}
+		if wanted == "" && len(d.profiles) == 1 {
+			return p, nil
+		}
+	}
+	return profile{}, fmt.Errorf("unknown quality profile; set %s.qualityProfile", d.service)
+}
+
+// folder returns the root folder a request downloads to and its disk, picked like the profile.
+func (d *destination) folder(request overseer.Request) (string, *disk, error) {
+	wanted := request.RootFolder
+	if wanted == "" {
+		wanted = d.instance.RootFolder
+	}
+	if wanted == "" && len(d.paths) == 1 {
+		wanted = d.paths[0]
+	}
+	if dk, ok := d.folders[strings.TrimRight(wanted, "/")]; ok && wanted != "" {
+		return wanted, dk, nil
+	}
+	return "", nil, fmt.Errorf("unknown root folder; set %s.rootFolder", d.service)
+}
+
+// estimate returns the title of a request and the most it downloads: its runtime at the size
+// limit of its quality profile. Series count the episodes of the requested seasons, or of
+// every season but the specials when none are listed.
+func estimate(ctx context.Context, client *overseer.OverseerClient, request overseer.Request, mbPerMinute float64) (string, int64, error) {
+	if request.Media.MediaType != "tv" {
+		details, err := client.GetMovieDetails(ctx, request.Media.TmdbId)
+		if err != nil {
+			return "", 0, err
+		}
+		if details.Runtime == 0 {
+			return details.Title, 0, fmt.Errorf("unknown runtime")
+		}
+		return details.Title, int64(float64(details.Runtime) * mbPerMinute * units.MB), nil
+	}
+
+	details, err := client.GetTVDetails(ctx, request.Media.TmdbId)
+	if err != nil {
+		return "", 0, err
+	}
+	var requested []int
+	for _, season := range request.Seasons {
+		requested = append(requested, season.SeasonNumber)
+	}
+	runtime := slices.Max(append([]int{0}, details.EpisodeRunTime...))
+	episodes := 0
+	for _, season := range details.Seasons {
+		if len(requested) > 0 && slices.Contains(requested, season.SeasonNumber) || len(requested) == 0 && season.SeasonNumber > 0 {
+			episodes += season.EpisodeCount
+		}
+	}
+	if runtime == 0 || episodes == 0 {
+		return details.Name, 0, fmt.Errorf("unknown runtime")
+	}
+	return details.Name, int64(float64(runtime*episodes) * mbPerMinute * units.MB), nil
+}
+
+// verdict is what the gate does with one pending request.
+type verdict struct {
+	request    overseer.Request
+	title      string
+	instance   string
+	rootFolder string
+	profile    string
+	// size is the estimate and free the space left above the reserve before the request.
+	size   int64
+	free   int64
+	action string
+	reason string
+}
+
+// label names a request in messages.
+func (v verdict) label() string {
+	return fmt.Sprintf("Request %d (%s by %s)", v.request.ID, v.title, v.request.RequestedBy.Name())
+}
+
+// decide checks whether a request fits on its disk. Approved requests take their estimate off
+// the disk, so later requests see the space that is left.
+func decide(ctx context.Context, client *overseer.OverseerClient, destinations []*destination, request overseer.Request, reserve int64, del bool) verdict {
+	v := verdict{request: request, title: request.Media.Title, action: "hold"}
+	if v.title == "" {
+		v.title = fmt.Sprintf("tmdb:%d", request.Media.TmdbId)
+	}
+	service, name := "radarr", "Radarr"
+	if request.Media.MediaType == "tv" {
+		service, name = "sonarr", "Sonarr"
+	}
+	var d *destination
+	for _, candidate := range destinations {
+		if candidate.service == service && cleanup.SentTo(request, candidate.instance) {
+			d = candidate
+			break
+		}
+	}
+	if d == nil {
+		v.reason = fmt.Sprintf("no %s instance for this request", name)
+		return v
+	}
+	v.instance = d.instance.Name
+
+	p, err := d.profile(request)
+	if err != nil {
+		v.reason = err.Error()
+		return v
+	}
+	v.profile = p.name
+	folder, dk, err := d.folder(request)
+	if err != nil {
+		v.reason = err.Error()
+		return v
+	}
+	v.rootFolder = folder
+	v.free = max(dk.free-reserve, 0)
+
+	title, size, err := estimate(ctx, client, request, p.mbPerMinute)
+	if title != "" {
+		v.title = title
+	}
+	if err != nil {
+		v.reason = err.Error()
+		return v
+	}
+	v.size = size
+	if size > v.free {
+		if del {
+			v.action = "decline"
+		}
+		v.reason = fmt.Sprintf("needs %.2f GB, %.2f GB free", units.ToGB(size), units.ToGB(v.free))
+		return v
+	}
+	v.action = "approve"
+	dk.free -= size
+	return v
+}
+
+// HandleGate approves the pending requests that fit on the disk of their root folder and holds,
+// or with Decline declines, the rest. Sizes are estimated from the runtime and the largest size
+// the quality profile allows. Requests are only reported unless Confirm is set.
+func HandleGate(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey string, opts GateOptions) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(opts.MinFree) > 0 {
+		conf.GateMinFree = opts.MinFree
+	}
+	var reserve int64
+	if conf.GateMinFree != "" {
+		var err error
+		if reserve, err = units.ParseBytes(conf.GateMinFree); err != nil {
+			return fmt.Errorf("invalid gate.minFree: %w", err)
+		}
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	if opts.Every == 0 {
+		return gate(ctx, r, conf, overseerClient, radarrAPIKey, sonarrAPIKey, reserve, opts)
+	}
+	for {
+		// A failed run is retried at the next one.
+		if err := gate(ctx, r, conf, overseerClient, radarrAPIKey, sonarrAPIKey, reserve, opts); err != nil {
+			fmt.Fprintln(os.Stderr, Red+err.Error()+Reset)
+		}
+		r.Infof("Next run at %s.\n", time.Now().Add(opts.Every).Format("15:04:05"))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Every):
+		}
+	}
+}
+
+// gate runs the gate once.
+func gate(ctx context.Context, r *render.Renderer, conf *config.Configuration, overseerClient *overseer.OverseerClient, radarrAPIKey, sonarrAPIKey string, reserve int64, opts GateOptions) error {
+	allRequests, err := overseerClient.GetRequests(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []overseer.Request
+	for _, request := range allRequests {
+		if request.Status == overseer.RequestStatusPending {
+			pending = append(pending, request)
+		}
+	}
+	if len(pending) == 0 {
+		r.Infof("No pending requests.\n")
+		return nil
+	}
+	// The oldest requests get the space first.
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+
+	destinations, err := loadDestinations(ctx, conf, radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+
+	var verdicts []verdict
+	counts := map[string]int{}
+	var records []render.Record
+	for _, request := range pending {
+		v := decide(ctx, overseerClient, destinations, request, reserve, opts.Decline)
+		verdicts = append(verdicts, v)
+		counts[v.action]++
+		var size, free any
+		if v.size > 0 {
+			size = render.Bytes(v.size)
+		}
+		if v.rootFolder != "" {
+			free = render.Bytes(v.free)
+		}
+		records = append(records, render.Record{
+			{Name: "id", Value: request.ID},
+			{Name: "title", Value: v.title},
+			{Name: "type", Value: request.Media.MediaType},
+			{Name: "requestedBy", Value: request.RequestedBy.Name()},
+			{Name: "instance", Value: v.instance},
+			{Name: "rootFolder", Value: v.rootFolder},
+			{Name: "qualityProfile", Value: v.profile},
+			{Name: "estimatedSize", Value: size},
+			{Name: "freeSpace", Value: free},
+			{Name: "action", Value: v.action},
+			{Name: "reason", Value: v.reason},
+		})
+	}
+	if err := r.Render(records, "id", "title", "type", "requestedBy", "rootFolder", "estimatedSize", "freeSpace", "action", "reason"); err != nil {
+		return err
+	}
+	r.Infof("%d to approve, %d to hold, %d to decline.\n", counts["approve"], counts["hold"], counts["decline"])
+	if counts["approve"]+counts["decline"] == 0 {
+		return nil
+	}
+	if !opts.Confirm {
+		r.Infof(Yellow + "Dry run: nothing was changed. Re-run with --yes to approve and decline these requests.\n" + Reset)
+		return nil
+	}
+
+	failed := 0
+	for _, v := range verdicts {
+		var err error
+		switch v.action {
+		case "approve":
+			if err = overseerClient.ApproveRequest(ctx, v.request.ID); err == nil {
+				plan.Annotate(fmt.Sprintf("Approve %s, %.2f GB estimated", v.label(), units.ToGB(v.size)), 0)
+				fmt.Printf(Green+"%s was approved (%.2f GB estimated).\n"+Reset, v.label(), units.ToGB(v.size))
+			}
+		case "decline":
+			reason := opts.Reason
+			if reason == "" {
+				reason = "Not enough free disk space: " + v.reason
+			}
+			if err = overseerClient.DeclineRequest(ctx, v.request.ID); err == nil {
+				plan.Annotate(fmt.Sprintf("Decline %s (%s)", v.label(), reason), 0)
+				fmt.Printf(Green+"%s was declined (%s).\n"+Reset, v.label(), reason)
+			}
+		}
+		switch {
+		case rest.IsNotFound(err):
+			fmt.Printf(Yellow+"%s was already removed from Overseer.\n"+Reset, v.label())
+		case err != nil:
+			failed++
+			fmt.Println(Red + err.Error() + Reset)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests could not be approved or declined", failed, counts["approve"]+counts["decline"])
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package requests
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/units"
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	ctx := context.Background()
+	radarrURL, _ := rest.NewTestReplay(t, "testdata", "radarr")
+	overseerURL, _ := rest.NewTestReplay(t, "testdata", "overseer")
+	instance := config.Instance{Name: "main", URL: radarrURL}
+	client := overseer.NewOverseerClient(overseerURL, "", rest.Options{})
+
+	movie := func(id, tmdbID int, is4K bool) overseer.Request {
+		return overseer.Request{ID: id, Media: overseer.Media{MediaType: "movie", TmdbId: tmdbID}, Is4K: is4K}
+	}
+	show := overseer.Request{ID: 803, Media: overseer.Media{MediaType: "tv", TmdbId: 301}}
+
+	type want struct {
+		title  string
+		action string
+		size   int64
+		reason string
+	}
+	// The profile allows qualities of up to 120 MB per minute: 12000 MB for Big Old and
+	// 10800 MB for Small New, on a disk with 20 GB free.
+	tests := []struct {
+		name     string
+		reserve  int64
+		del      bool
+		requests []overseer.Request
+		want     []want
+	}{
+		{
+			name:     "oldest first",
+			requests: []overseer.Request{movie(801, 101, false), movie(802, 102, false)},
+			want: []want{
+				{"Big Old", "approve", 12000 * units.MB, ""},
+				{"Small New", "hold", 10800 * units.MB, "needs 10.55 GB, 8.28 GB free"},
+			},
+		},
+		{
+			name:     "decline",
+			del:      true,
+			requests: []overseer.Request{movie(801, 101, false), movie(802, 102, false)},
+			want: []want{
+				{"Big Old", "approve", 12000 * units.MB, ""},
+				{"Small New", "decline", 10800 * units.MB, "needs 10.55 GB, 8.28 GB free"},
+			},
+		},
+		{
+			name:     "reserve",
+			reserve:  10 * units.GB,
+			requests: []overseer.Request{movie(801, 101, false), movie(802, 102, false)},
+			want: []want{
+				{"Big Old", "hold", 12000 * units.MB, "needs 11.72 GB, 10.00 GB free"},
+				{"Small New", "hold", 10800 * units.MB, "needs 10.55 GB, 10.00 GB free"},
+			},
+		},
+		{
+			name:     "no instance",
+			del:      true,
+			requests: []overseer.Request{movie(804, 101, true), show},
+			want: []want{
+				{"tmdb:101", "hold", 0, "no Radarr instance for this request"},
+				{"tmdb:301", "hold", 0, "no Sonarr instance for this request"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := fetchRadarr(ctx, instance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			destinations := []*destination{newDestination("radarr", instance, s)}
+
+			for i, request := range tt.requests {
+				v := decide(ctx, client, destinations, request, tt.reserve, tt.del)
+				got := want{v.title, v.action, v.size, v.reason}
+				if got != tt.want[i] {
+					t.Errorf("request %d: got %+v, want %+v", request.ID, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package requests
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/series"
+	"fmt"
+)
+
+// Library holds the Radarr movies and Sonarr series, indexed by the IDs Overseer knows them by.
+type Library struct {
+	Movies []movies.Movie
+	Series []series.Series
+
+	moviesByTmdb map[int][]movies.Movie
+	seriesByTvdb map[int][]series.Series
+	seriesByTmdb map[int][]series.Series
+}
+
+// Item is the Radarr movie or Sonarr series a request resolved to. Exactly one of Movie
+// and Series is set.
+type Item struct {
+	Title  string
+	Size   int64
+	Movie  *movies.Movie
+	Series *series.Series
+}
+
+// Key identifies the movie or series across instances.
+func (i Item) Key() string {
+	if i.Movie != nil {
+		return fmt.Sprintf("movie/%s/%d", i.Movie.Instance.Name, i.Movie.ID)
+	}
+	return fmt.Sprintf("series/%s/%d", i.Series.Instance.Name, i.Series.ID)
+}
+
+// Instance is the Radarr or Sonarr instance the item is on.
+func (i Item) Instance() config.Instance {
+	if i.Movie != nil {
+		return i.Movie.Instance
+	}
+	return i.Series.Instance
+}
+
+// LoadLibrary fetches the movies and series of every selected instance. Services without any
+// configured or selected instance are left out, so requests can be managed with only Overseer
+// configured.
+func LoadLibrary(ctx context.Context, conf *config.Configuration, radarrAPIKey, sonarrAPIKey string) (*Library, error) {
+	l := &Library{
+		moviesByTmdb: map[int][]movies.Movie{},
+		seriesByTvdb: map[int][]series.Series{},
+		seriesByTmdb: map[int][]series.Series{},
+	}
+	radarrInstances, sonarrInstances, err := conf.Both(radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	if l.Movies, err = movies.FetchMovies(ctx, radarrInstances); err != nil {
+		return nil, err
+	}
+	if l.Series, err = series.FetchSeries(ctx, sonarrInstances); err != nil {
+		return nil, err
+	}
+	for _, m := range l.Movies {
+		l.moviesByTmdb[m.TMDBID] = append(l.moviesByTmdb[m.TMDBID], m)
+	}
+	for _, s := range l.Series {
+		l.seriesByTvdb[s.TvdbID] = append(l.seriesByTvdb[s.TvdbID], s)
+		if s.TmdbID != 0 {
+			l.seriesByTmdb[s.TmdbID] = append(l.seriesByTmdb[s.TmdbID], s)
+		}
+	}
+	return l, nil
+}
+
+// Resolve finds the movie or series a request was downloaded as. When a title exists on several
+// instances, the one the request was sent to is preferred.
+func (l *Library) Resolve(request overseer.Request) (Item, bool) {
+	media := request.Media
+	if media.MediaType == "tv" {
+		candidates := l.seriesByTvdb[media.TvdbId]
+		if len(candidates) == 0 {
+			candidates = l.seriesByTmdb[media.TmdbId]
+		}
+		if len(candidates) == 0 {
+			return Item{}, false
+		}
+		s := candidates[0]
+		for _, c := range candidates {
+			if cleanup.SentTo(request, c.Instance) {
+				s = c
+				break
+			}
+		}
+		return Item{Title: s.Title, Size: int64(s.Statistics.SizeOnDisk), Series: &s}, true
+	}
+
+	candidates := l.moviesByTmdb[media.TmdbId]
+	if len(candidates) == 0 {
+		return Item{}, false
+	}
+	m := candidates[0]
+	for _, c := range candidates {
+		if cleanup.SentTo(request, c.Instance) {
+			m = c
+			break
+		}
+	}
+	return Item{Title: m.Title, Size: int64(m.Statistics.SizeOnDisk), Movie: &m}, true
+}
+
+// Title names the media of a request, falling back to its TMDB ID when it isn't in the library.
+func (l *Library) Title(request overseer.Request) string {
+	if item, ok := l.Resolve(request); ok {
+		return item.Title
+	}
+	if request.Media.Title != "" {
+		return request.Media.Title
+	}
+	return fmt.Sprintf("tmdb:%d", request.Media.TmdbId)
+}
@@ -0,0 +1,181 @@
+package requests
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// purgeItem is one movie or series requested by the purged user, or a request whose media
+// is no longer on disk.
+type purgeItem struct {
+	item     Item
+	resolved bool
+	requests []overseer.Request
+	// keep explains why the media stays; only the user's requests are deleted then.
+	keep string
+}
+
+// HandlePurgeUser deletes everything an Overseer user requested: the Radarr movies and Sonarr
+// series and the user's requests. Items other users also requested are kept, as are series that
+// still air unless includeOngoing is set. The plan is applied after one confirmation (or confirm).
+func HandlePurgeUser(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup, query string, includeOngoing, confirm bool) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+	if err != nil {
+		return err
+	}
+	library, err := LoadLibrary(ctx, conf, radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+
+	users := map[int]overseer.User{}
+	var own []overseer.Request
+	for _, request := range overseerData.Requests {
+		if request.RequestedBy.Matches(query) {
+			users[request.RequestedBy.ID] = request.RequestedBy
+			own = append(own, request)
+		}
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("no requests found for user %q", query)
+	}
+	if len(users) > 1 {
+		var names []string
+		for _, u := range users {
+			names = append(names, fmt.Sprintf("%s (id %d)", u.Name(), u.ID))
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%q matches several users: %s; use the user ID instead", query, strings.Join(names, ", "))
+	}
+	user := own[0].RequestedBy
+
+	// Other users' requests decide which media must stay.
+	otherUsers := map[int][]string{}
+	for _, request := range overseerData.Requests {
+		if request.RequestedBy.ID != user.ID {
+			otherUsers[request.Media.Id] = append(otherUsers[request.Media.Id], request.RequestedBy.Name())
+		}
+	}
+
+	var items []*purgeItem
+	byKey := map[string]*purgeItem{}
+	for _, request := range own {
+		item, ok := library.Resolve(request)
+		if !ok {
+			items = append(items, &purgeItem{item: Item{Title: library.Title(request)}, requests: []overseer.Request{request}, keep: "not on disk"})
+			continue
+		}
+		if existing, ok := byKey[item.Key()]; ok {
+			existing.requests = append(existing.requests, request)
+			continue
+		}
+		p := &purgeItem{item: item, resolved: true, requests: []overseer.Request{request}}
+		if others := otherUsers[request.Media.Id]; len(others) > 0 {
+			p.keep = "also requested by " + strings.Join(unique(others), ", ")
+		} else if item.Series != nil && series.Ongoing(item.Series.Series) && !includeOngoing {
+			p.keep = series.OngoingReason(item.Series.Series) + "; use --include-ongoing"
+		}
+		byKey[item.Key()] = p
+		items = append(items, p)
+	}
+
+	var records []render.Record
+	var total int64
+	for _, p := range items {
+		var ids []string
+		for _, request := range p.requests {
+			ids = append(ids, strconv.Itoa(request.ID))
+		}
+		action := "delete"
+		var size any
+		var instance any
+		if p.resolved {
+			instance = p.item.Instance().Name
+		}
+		if p.keep != "" {
+			action = "delete requests only: " + p.keep
+		} else {
+			size = render.Bytes(p.item.Size)
+			total += p.item.Size
+		}
+		records = append(records, render.Record{
+			{Name: "title", Value: p.item.Title},
+			{Name: "type", Value: p.requests[0].Media.MediaType},
+			{Name: "instance", Value: instance},
+			{Name: "requests", Value: ids},
+			{Name: "sizeOnDisk", Value: size},
+			{Name: "action", Value: action},
+		})
+	}
+	r.Infof("Requests of %s (id %d):\n", user.Name(), user.ID)
+	if err := r.Render(records, "title", "type", "requests", "sizeOnDisk", "action"); err != nil {
+		return err
+	}
+	r.Infof("%d requests, %.2f GB will be freed.\n", len(own), units.ToGB(total))
+
+	if !confirm {
+		fmt.Printf(Yellow+"Delete everything %s requested? (y/N): "+Reset, user.Name())
+		reader := bufio.NewReader(os.Stdin)
+		input, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(input)) != "y" {
+			fmt.Println("Nothing was deleted.")
+			return nil
+		}
+	}
+
+	for _, p := range items {
+		if p.keep != "" {
+			continue
+		}
+		if p.item.Movie != nil {
+			movies.DeleteMovie(ctx, overseerData, *p.item.Movie)
+		} else {
+			series.DeleteSeries(ctx, overseerData, *p.item.Series)
+		}
+	}
+	// Requests that weren't removed along with their media.
+	for _, p := range items {
+		for _, request := range p.requests {
+			if !overseerData.Removed(request) {
+				overseerData.RemoveRequest(ctx, request, p.item.Title)
+			}
+		}
+	}
+	return nil
+}
+
+// unique returns the distinct values in their original order.
+func unique(values []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
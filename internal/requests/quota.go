@@ -0,0 +1,248 @@
+package requests
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// unlimited exempts a user from the default budget in quota.users.
+const unlimited = "unlimited"
+
+// Quotas are the storage budgets of Overseer users in bytes. A user's entry in Users, keyed by
+// user ID or name, overrides Default; a budget of zero means the user has none.
+type Quotas struct {
+	Default int64
+	Users   map[string]int64
+}
+
+// NewQuotas reads quota.default and quota.users from the configuration.
+func NewQuotas(conf *config.Configuration) (Quotas, error) {
+	q := Quotas{Users: map[string]int64{}}
+	if conf.QuotaDefault != "" {
+		size, err := units.ParseBytes(conf.QuotaDefault)
+		if err != nil {
+			return q, fmt.Errorf("invalid quota.default: %w", err)
+		}
+		q.Default = size
+	}
+	for user, value := range conf.QuotaUsers {
+		if strings.EqualFold(strings.TrimSpace(value), unlimited) {
+			q.Users[user] = 0
+			continue
+		}
+		size, err := units.ParseBytes(value)
+		if err != nil {
+			return q, fmt.Errorf("invalid quota for user %q: %w", user, err)
+		}
+		q.Users[user] = size
+	}
+	return q, nil
+}
+
+// IsZero reports whether no user has a budget.
+func (q Quotas) IsZero() bool {
+	if q.Default > 0 {
+		return false
+	}
+	for _, budget := range q.Users {
+		if budget > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Budget returns the budget of a user, or false if the user has none. An entry for the user's ID
+// wins over entries for their names, which are tried in sorted order.
+func (q Quotas) Budget(user overseer.User) (int64, bool) {
+	if budget, ok := q.Users[strconv.Itoa(user.ID)]; ok {
+		return budget, budget > 0
+	}
+	queries := make([]string, 0, len(q.Users))
+	for query := range q.Users {
+		queries = append(queries, query)
+	}
+	sort.Strings(queries)
+	for _, query := range queries {
+		if budget := q.Users[query]; user.Matches(query) {
+			return budget, budget > 0
+		}
+	}
+	return q.Default, q.Default > 0
+}
+
+// Usage is the disk space the requests of one Overseer user take up.
+type Usage struct {
+	User     overseer.User
+	Requests []overseer.Request
+	Items    []Item
+	Size     int64
+	Pending  []overseer.Request
+	// Unresolved counts requests whose media isn't in Radarr or Sonarr (anymore).
+	Unresolved int
+
+	seen map[string]bool
+}
+
+// UsageByUser adds up the size on disk of the requests of every user, in the order the users
+// first appear. An item requested by several users counts in full for each of them.
+func UsageByUser(allRequests []overseer.Request, library *Library) []*Usage {
+	byUser := map[int]*Usage{}
+	var order []*Usage
+	for _, request := range allRequests {
+		u, ok := byUser[request.RequestedBy.ID]
+		if !ok {
+			u = &Usage{User: request.RequestedBy, seen: map[string]bool{}}
+			byUser[request.RequestedBy.ID] = u
+			order = append(order, u)
+		}
+		u.Requests = append(u.Requests, request)
+		if request.Status == overseer.RequestStatusPending {
+			u.Pending = append(u.Pending, request)
+		}
+		item, ok := library.Resolve(request)
+		if !ok {
+			u.Unresolved++
+			continue
+		}
+		if key := item.Key(); !u.seen[key] {
+			u.seen[key] = true
+			u.Items = append(u.Items, item)
+			u.Size += item.Size
+		}
+	}
+	return order
+}
+
+// quotaReason is the default decline reason for a user over budget.
+func quotaReason(used, budget int64) string {
+	return fmt.Sprintf("Storage quota exceeded: %.2f GB of %.2f GB used", units.ToGB(used), units.ToGB(budget))
+}
+
+// HandleQuota reports how much of their storage budget every Overseer user uses. With decline the
+// pending requests of users over budget are declined after one confirmation (or confirm). Each
+// decline is printed with reason, or a default reason naming the usage; Overseer has no way to
+// pass it on to the users.
+func HandleQuota(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey string, decline, confirm bool, reason string) error {
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	r := render.New(conf.Output, conf.Columns)
+	quotas, err := NewQuotas(conf)
+	if err != nil {
+		return err
+	}
+	if quotas.IsZero() {
+		return fmt.Errorf("no storage budgets are configured; set quota.default or quota.users")
+	}
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	allRequests, err := overseerClient.GetRequests(ctx)
+	if err != nil {
+		return err
+	}
+	library, err := LoadLibrary(ctx, conf, radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+
+	type userQuota struct {
+		usage   *Usage
+		budget  int64
+		percent float64
+	}
+	var rows []userQuota
+	for _, u := range UsageByUser(allRequests, library) {
+		budget, _ := quotas.Budget(u.User)
+		percent := -1.0
+		if budget > 0 {
+			percent = float64(u.Size) / float64(budget) * 100
+		}
+		rows = append(rows, userQuota{u, budget, percent})
+	}
+	// Users without a budget come last, largest first.
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].percent != rows[j].percent {
+			return rows[i].percent > rows[j].percent
+		}
+		return rows[i].usage.Size > rows[j].usage.Size
+	})
+
+	var over []userQuota
+	var records []render.Record
+	for _, q := range rows {
+		var budget, remaining, percent any
+		exceeded := q.budget > 0 && q.usage.Size > q.budget
+		if q.budget > 0 {
+			budget = render.Bytes(q.budget)
+			remaining = render.Bytes(max(q.budget-q.usage.Size, 0))
+			percent = int(math.Round(q.percent))
+		}
+		if exceeded {
+			over = append(over, q)
+		}
+		records = append(records, render.Record{
+			{Name: "user", Value: q.usage.User.Name()},
+			{Name: "userId", Value: q.usage.User.ID},
+			{Name: "budget", Value: budget},
+			{Name: "sizeOnDisk", Value: render.Bytes(q.usage.Size)},
+			{Name: "remaining", Value: remaining},
+			{Name: "percentUsed", Value: percent},
+			{Name: "over", Value: exceeded},
+			{Name: "pending", Value: len(q.usage.Pending)},
+		})
+	}
+	if err := r.Render(records, "user", "budget", "sizeOnDisk", "remaining", "percentUsed", "over", "pending"); err != nil {
+		return err
+	}
+
+	var pending int
+	for _, q := range over {
+		pending += len(q.usage.Pending)
+	}
+	r.Infof("%d of %d users are over budget with %d pending requests.\n", len(over), len(rows), pending)
+	if !decline || pending == 0 {
+		return nil
+	}
+	if !confirm {
+		fmt.Printf(Yellow+"Decline the %d pending requests of users over budget? (y/N): "+Reset, pending)
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(input)) != "y" {
+			fmt.Println("Nothing was declined.")
+			return nil
+		}
+	}
+
+	for _, q := range over {
+		why := reason
+		if why == "" {
+			why = quotaReason(q.usage.Size, q.budget)
+		}
+		for _, request := range q.usage.Pending {
+			label := fmt.Sprintf("Request %d (%s by %s)", request.ID, library.Title(request), q.usage.User.Name())
+			if err := overseerClient.DeclineRequest(ctx, request.ID); rest.IsNotFound(err) {
+				fmt.Printf(Yellow+"%s was already removed from Overseer.\n"+Reset, label)
+			} else if err != nil {
+				fmt.Println(Red + err.Error() + Reset)
+			} else {
+				plan.Annotate(fmt.Sprintf("Decline Overseer %s (%s)", label, why), 0)
+				fmt.Printf(Green+"%s was declined (%s).\n"+Reset, label, why)
+			}
+		}
+	}
+	return nil
+}
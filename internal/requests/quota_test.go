@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/units"
+	"testing"
+)
+
+func TestBudget(t *testing.T) {
+	quotas := Quotas{
+		Default: 2 * units.GB,
+		Users: map[string]int64{
+			"alice":           0,
+			"2":               5 * units.GB,
+			"Bob":             7 * units.GB,
+			"carol":           8 * units.GB,
+			"carol@localhost": 9 * units.GB,
+		},
+	}
+	tests := []struct {
+		name       string
+		user       overseer.User
+		want       int64
+		wantBudget bool
+	}{
+		{"unlimited", overseer.User{ID: 1, Username: "alice"}, 0, false},
+		{"ID wins over name", overseer.User{ID: 2, Username: "bob"}, 5 * units.GB, true},
+		{"first name in sorted order", overseer.User{ID: 3, Username: "carol", Email: "carol@localhost"}, 8 * units.GB, true},
+		{"default", overseer.User{ID: 4, Username: "dave"}, 2 * units.GB, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map order varies between runs, so ask repeatedly.
+			for i := 0; i < 20; i++ {
+				got, ok := quotas.Budget(tt.user)
+				if got != tt.want || ok != tt.wantBudget {
+					t.Fatalf("Budget = %d, %v, want %d, %v", got, ok, tt.want, tt.wantBudget)
+				}
+			}
+		})
+	}
+}
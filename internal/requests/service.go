@@ -0,0 +1,269 @@
+package requests
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// Filter selects requests. Zero values mean "don't filter on this field"; all set criteria must match.
+type Filter struct {
+	// Statuses are RequestStatus names such as "pending" or "approved".
+	Statuses []string
+	// User is a user ID or a username, display name, Plex username or email.
+	User string
+	// MediaType is "movie" or "tv".
+	MediaType string
+	Is4K      *bool
+	OlderThan time.Duration
+}
+
+// Validate checks the status and media type names.
+func (f Filter) Validate() error {
+	for _, status := range f.Statuses {
+		if !isStatus(status) {
+			var names []string
+			for i := overseer.RequestStatusPending; i <= overseer.RequestStatusCompleted; i++ {
+				names = append(names, overseer.RequestStatusName(i))
+			}
+			return fmt.Errorf("invalid status %q, expected one of %s", status, strings.Join(names, ", "))
+		}
+	}
+	if f.MediaType != "" && f.MediaType != "movie" && f.MediaType != "tv" {
+		return fmt.Errorf("invalid media type %q, expected movie or tv", f.MediaType)
+	}
+	return nil
+}
+
+func isStatus(name string) bool {
+	for _, known := range overseer.RequestStatusNames {
+		if strings.EqualFold(known, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZero reports whether the filter selects every request.
+func (f Filter) IsZero() bool {
+	return len(f.Statuses) == 0 && f.User == "" && f.MediaType == "" && f.Is4K == nil && f.OlderThan == 0
+}
+
+// Matches reports whether a request satisfies every criterion.
+func (f Filter) Matches(request overseer.Request, now time.Time) bool {
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if strings.EqualFold(status, overseer.RequestStatusName(request.Status)) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.User != "" && !request.RequestedBy.Matches(f.User) {
+		return false
+	}
+	if f.MediaType != "" && request.Media.MediaType != f.MediaType {
+		return false
+	}
+	if f.Is4K != nil && request.Is4K != *f.Is4K {
+		return false
+	}
+	if f.OlderThan > 0 && now.Sub(request.CreatedAt) < f.OlderThan {
+		return false
+	}
+	return true
+}
+
+// RequestRecord describes a request for listings, using the field names of the Overseer API.
+func RequestRecord(request overseer.Request, library *Library, now time.Time) render.Record {
+	var seasons []string
+	for _, season := range request.Seasons {
+		seasons = append(seasons, strconv.Itoa(season.SeasonNumber))
+	}
+	var size any
+	if item, ok := library.Resolve(request); ok {
+		size = render.Bytes(item.Size)
+	}
+	return render.Record{
+		{Name: "id", Value: request.ID},
+		{Name: "status", Value: overseer.RequestStatusName(request.Status)},
+		{Name: "type", Value: request.Media.MediaType},
+		{Name: "title", Value: library.Title(request)},
+		{Name: "seasons", Value: seasons},
+		{Name: "requestedBy", Value: request.RequestedBy.Name()},
+		{Name: "is4k", Value: request.Is4K},
+		{Name: "serverId", Value: request.ServerID},
+		{Name: "createdAt", Value: request.CreatedAt},
+		{Name: "updatedAt", Value: request.UpdatedAt},
+		{Name: "ageDays", Value: int(now.Sub(request.CreatedAt) / units.Day)},
+		{Name: "mediaStatus", Value: request.Media.Status},
+		{Name: "sizeOnDisk", Value: size},
+		{Name: "tmdbId", Value: request.Media.TmdbId},
+		{Name: "tvdbId", Value: request.Media.TvdbId},
+	}
+}
+
+// load fetches the requests matching a filter together with the library used to name them.
+func load(ctx context.Context, overseerAPIKey string, filter Filter) (*render.Renderer, *overseer.OverseerClient, *Library, []overseer.Request, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// Get configuration
+	conf := config.GetConfig()
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	r := render.New(conf.Output, conf.Columns)
+	r.Infof("Overseer API Endpoint: %v\n", conf.OverseerURL)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	all, err := overseerClient.GetRequests(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	library, err := LoadLibrary(ctx, conf, "", "")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	now := time.Now()
+	var selected []overseer.Request
+	for _, request := range all {
+		if filter.Matches(request, now) {
+			selected = append(selected, request)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].CreatedAt.After(selected[j].CreatedAt)
+	})
+	return r, overseerClient, library, selected, nil
+}
+
+// HandleList lists the requests matching a filter, newest first.
+func HandleList(ctx context.Context, overseerAPIKey string, filter Filter) error {
+	r, _, library, selected, err := load(ctx, overseerAPIKey, filter)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var records []render.Record
+	for _, request := range selected {
+		records = append(records, RequestRecord(request, library, now))
+	}
+	return r.Render(records, "id", "status", "type", "title", "requestedBy", "is4k", "createdAt", "sizeOnDisk")
+}
+
+// Action is something that can be done to a request.
+type Action struct {
+	// Name is the verb used in messages, e.g. "approve".
+	Name string
+	// Past is the participle used in messages, e.g. "approved".
+	Past string
+	// Status is the request status the action applies to, if it only applies to one.
+	Status int
+	do     func(client *overseer.OverseerClient, ctx context.Context, id int) error
+}
+
+// Actions are the request actions available as commands.
+var (
+	Approve = Action{Name: "approve", Past: "approved", Status: overseer.RequestStatusPending, do: (*overseer.OverseerClient).ApproveRequest}
+	Decline = Action{Name: "decline", Past: "declined", Status: overseer.RequestStatusPending, do: (*overseer.OverseerClient).DeclineRequest}
+	Retry   = Action{Name: "retry", Past: "retried", Status: overseer.RequestStatusFailed, do: (*overseer.OverseerClient).RetryRequest}
+	Delete  = Action{Name: "delete", Past: "deleted", do: (*overseer.OverseerClient).DeleteRequest}
+)
+
+// HandleAction applies an action to the given request IDs, or to every request matching the filter.
+// Requests picked by a filter are only reported unless confirm is set. Without a status filter,
+// actions that only apply to one status select requests with that status.
+func HandleAction(ctx context.Context, overseerAPIKey string, action Action, ids []int, filter Filter, confirm bool) error {
+	if len(ids) == 0 && filter.IsZero() {
+		return fmt.Errorf("give the IDs of the requests to %s, or select them with filters such as --status or --user", action.Name)
+	}
+	if len(ids) == 0 && len(filter.Statuses) == 0 && action.Status != 0 {
+		filter.Statuses = []string{overseer.RequestStatusName(action.Status)}
+	}
+
+	r, overseerClient, library, selected, err := load(ctx, overseerAPIKey, filter)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		byID := map[int]overseer.Request{}
+		for _, request := range selected {
+			byID[request.ID] = request
+		}
+		selected = nil
+		for _, id := range ids {
+			request, ok := byID[id]
+			if !ok {
+				return fmt.Errorf("request %d not found or not matching the filters", id)
+			}
+			selected = append(selected, request)
+		}
+	}
+	if len(selected) == 0 {
+		r.Infof("No requests match the given criteria.\n")
+		return nil
+	}
+
+	now := time.Now()
+	if len(ids) == 0 {
+		var records []render.Record
+		for _, request := range selected {
+			records = append(records, RequestRecord(request, library, now))
+		}
+		if err := r.Render(records, "id", "status", "type", "title", "requestedBy", "is4k", "createdAt", "sizeOnDisk"); err != nil {
+			return err
+		}
+		r.Infof("%d requests matched.\n", len(selected))
+		if !confirm {
+			r.Infof(Yellow+"Dry run: nothing was changed. Re-run with --yes to %s these requests.\n"+Reset, action.Name)
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, request := range selected {
+		what := fmt.Sprintf("request %d (%s by %s)", request.ID, library.Title(request), request.RequestedBy.Name())
+		label := strings.ToUpper(what[:1]) + what[1:]
+		if action.Status != 0 && request.Status != action.Status {
+			fmt.Printf(Yellow+"%s is %s; only %s requests can be %s.\n"+Reset, label, overseer.RequestStatusName(request.Status), overseer.RequestStatusName(action.Status), action.Past)
+			continue
+		}
+		err := action.do(overseerClient, ctx, request.ID)
+		switch {
+		case rest.IsNotFound(err):
+			fmt.Printf(Yellow+"%s was already removed from Overseer.\n"+Reset, label)
+		case err != nil:
+			failed++
+			fmt.Println(Red + err.Error() + Reset)
+		default:
+			plan.Annotate(fmt.Sprintf("%s %s", strings.ToUpper(action.Name[:1])+action.Name[1:], what), 0)
+			fmt.Printf(Green+"%s was successfully %s.\n"+Reset, label, action.Past)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests could not be %s", failed, len(selected), action.Past)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package series
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/add"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// lookupTerm turns a TVDB ID or a title into a Sonarr lookup term.
+func lookupTerm(query string) string {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(strings.ToLower(query), "tvdb:") {
+		return query
+	}
+	if _, err := strconv.Atoi(query); err == nil {
+		return "tvdb:" + query
+	}
+	return query
+}
+
+// HandleAdd looks a series up by TVDB ID or title and adds it to Sonarr with every season
+// monitored, or requests all its seasons through Overseer. A title matching several series asks
+// which one to add.
+func HandleAdd(ctx context.Context, sonarrAPIKey, overseerAPIKey, query string, opts add.Options) error {
+	// Get configuration
+	conf := config.GetConfig()
+	instances, err := conf.Sonarr(sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	if len(instances) > 1 {
+		return fmt.Errorf("several Sonarr instances are selected; pick the one to add to with --instance")
+	}
+	instance := instances[0]
+	r := render.New(conf.Output, conf.Columns)
+	client := sonarr.NewSonarrClient(instance.URL, instance.APIKey, instance.Options)
+
+	results, err := client.LookupSeries(ctx, lookupTerm(query))
+	if err != nil {
+		return err
+	}
+	series, err := pickSeries(r, query, results)
+	if err != nil || series == nil {
+		return err
+	}
+	title := fmt.Sprintf("%s (%d)", series.Title, series.Year)
+	if series.ID != 0 {
+		r.Infof(Yellow+"'%s' is already in %s.\n"+Reset, title, sonarrName(instance))
+		return nil
+	}
+
+	profiles, folders, err := choices(ctx, client)
+	if err != nil {
+		return err
+	}
+	profileID, rootFolder, err := add.Resolve("Sonarr", instance, profiles, folders, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Overseer {
+		if series.TmdbID == 0 {
+			return fmt.Errorf("Sonarr has no TMDB ID for '%s', which Overseer needs; add it to Sonarr directly instead", title)
+		}
+		if len(overseerAPIKey) > 0 {
+			conf.OverseerAPIKey = overseerAPIKey
+		}
+		overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+		// Every season is requested except specials.
+		request := overseer.NewRequest{MediaType: "tv", MediaID: series.TmdbID, TvdbID: series.TvdbID, Is4K: instance.Is4K, ServerID: instance.ServerID, ProfileID: profileID, RootFolder: rootFolder}
+		for _, season := range series.Seasons {
+			if season.SeasonNumber > 0 {
+				request.Seasons = append(request.Seasons, season.SeasonNumber)
+			}
+		}
+		return add.Request(ctx, overseerClient, request, title, opts.User)
+	}
+
+	// Specials are left unmonitored, like Sonarr does when adding a series.
+	seasons := make([]sonarr.Season, len(series.Seasons))
+	copy(seasons, series.Seasons)
+	for i := range seasons {
+		seasons[i].Monitored = seasons[i].SeasonNumber > 0
+	}
+	seriesType := series.SeriesType
+	if seriesType == "" {
+		seriesType = "standard"
+	}
+	_, err = client.AddSeries(ctx, sonarr.NewSeries{
+		Title:            series.Title,
+		Year:             series.Year,
+		TvdbID:           series.TvdbID,
+		QualityProfileID: profileID,
+		RootFolderPath:   rootFolder,
+		SeriesType:       seriesType,
+		SeasonFolder:     true,
+		Monitored:        true,
+		Seasons:          seasons,
+		Tags:             []int{},
+		AddOptions:       sonarr.AddOptions{Monitor: "all", SearchForMissingEpisodes: opts.Search},
+	})
+	if err != nil {
+		return err
+	}
+	plan.Annotate(fmt.Sprintf("Add series '%s' to %s", title, sonarrName(instance)), 0)
+	if opts.Search {
+		fmt.Printf(Green+"'%s' was added to %s and its missing episodes are being searched for.\n"+Reset, title, sonarrName(instance))
+	} else {
+		fmt.Printf(Green+"'%s' was added to %s.\n"+Reset, title, sonarrName(instance))
+	}
+	return nil
+}
+
+// pickSeries returns the only lookup result, or asks which one to use. It returns nil when
+// nothing was picked.
+func pickSeries(r *render.Renderer, query string, results []sonarr.Series) (*sonarr.Series, error) {
+	switch len(results) {
+	case 0:
+		return nil, fmt.Errorf("no series found for %q", query)
+	case 1:
+		return &results[0], nil
+	}
+
+	var records []render.Record
+	for i, series := range results {
+		records = append(records, render.Record{
+			{Name: "index", Value: i + 1},
+			{Name: "title", Value: series.Title},
+			{Name: "year", Value: series.Year},
+			{Name: "tvdbId", Value: series.TvdbID},
+			{Name: "status", Value: series.Status},
+			{Name: "inLibrary", Value: series.ID != 0},
+		})
+	}
+	if err := r.Render(records, "index", "title", "year", "tvdbId", "status", "inLibrary"); err != nil {
+		return nil, err
+	}
+	fmt.Print(Green + "Select the series to add (empty = cancel): " + Reset)
+	input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		fmt.Println("No series selected. Exiting.")
+		return nil, nil
+	}
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(results) {
+		return nil, fmt.Errorf("invalid selection: %s", input)
+	}
+	return &results[index-1], nil
+}
+
+// choices returns Sonarr's quality profiles and root folders.
+func choices(ctx context.Context, client *sonarr.SonarrClient) ([]add.Choice, []add.Choice, error) {
+	profiles, err := client.GetQualityProfiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, err := client.GetRootFolders(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var profileChoices, folderChoices []add.Choice
+	for _, profile := range profiles {
+		profileChoices = append(profileChoices, add.Choice{ID: profile.ID, Name: profile.Name})
+	}
+	for _, folder := range folders {
+		folderChoices = append(folderChoices, add.Choice{ID: folder.ID, Name: folder.Path})
+	}
+	return profileChoices, folderChoices, nil
+}
@@ -0,0 +1,124 @@
+package series
+
+import (
+	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"strings"
+)
+
+// Airing states of a series.
+const (
+	AiringEnded      = "ended"
+	AiringContinuing = "continuing"
+	AiringUpcoming   = "upcoming"
+)
+
+// Airing classifies a series as ended, continuing or upcoming, i.e. announced but not aired yet.
+func Airing(series sonarr.Series) string {
+	switch {
+	case series.Ended || series.Status == "ended":
+		return AiringEnded
+	case series.Status == "upcoming":
+		return AiringUpcoming
+	default:
+		return AiringContinuing
+	}
+}
+
+// Ongoing reports whether a series still gets new episodes. Deleting such a series needs an extra
+// confirmation, since it is usually a mistake.
+func Ongoing(series sonarr.Series) bool {
+	return Airing(series) != AiringEnded
+}
+
+// OngoingReason describes why a series is protected, e.g. "continuing, next episode airs 2026-10-25".
+func OngoingReason(series sonarr.Series) string {
+	if series.NextAiring.IsZero() {
+		return Airing(series)
+	}
+	return fmt.Sprintf("%s, next episode airs %s", Airing(series), series.NextAiring.Format("2006-01-02"))
+}
+
+// AiringSeasons returns the numbers of the seasons of a series that have an episode scheduled.
+func AiringSeasons(series sonarr.Series) map[int]bool {
+	airing := map[int]bool{}
+	for _, season := range series.Seasons {
+		airing[season.SeasonNumber] = !season.Statistics.NextAiring.IsZero()
+	}
+	return airing
+}
+
+// ongoingChoice is the answer to the extra confirmation for an ongoing series.
+type ongoingChoice int
+
+const (
+	ongoingSkip ongoingChoice = iota
+	ongoingDelete
+	ongoingKeepMonitoring
+)
+
+// confirmOngoing asks how to handle the deletion of an ongoing series. Deleting the series
+// requires typing "delete"; anything unrecognised skips it.
+func confirmOngoing(reader *bufio.Reader, selectedSeries Series) ongoingChoice {
+	fmt.Printf(Yellow+"'%s' is %s.\n"+Reset, selectedSeries.Title, OngoingReason(selectedSeries.Series))
+	fmt.Printf(Yellow+"Type 'delete' to delete the entire series anyway (%.2f GB), 'k' to delete its files but keep monitoring future episodes, or press enter to skip: "+Reset, units.ToGB(int64(selectedSeries.Statistics.SizeOnDisk)))
+	input, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "delete":
+		return ongoingDelete
+	case "k":
+		return ongoingKeepMonitoring
+	default:
+		return ongoingSkip
+	}
+}
+
+// KeepMonitoring deletes every episode file of a series but keeps the series in Sonarr for
+// future episodes. Seasons that are done are unmonitored, the airing season stays monitored with
+// its deleted episodes unmonitored, and new seasons are monitored as they are announced.
+func KeepMonitoring(ctx context.Context, selectedSeries Series) {
+	sonarrClient := selectedSeries.client
+	files, err := sonarrClient.GetEpiosdeFilesForSeries(ctx, selectedSeries.ID, nil)
+	if err != nil {
+		fmt.Printf("Error getting episode files: %v\n", err)
+		return
+	}
+	episodes, err := sonarrClient.GetEpisodes(ctx, selectedSeries.ID)
+	if err != nil {
+		fmt.Printf("Error getting episodes: %v\n", err)
+		return
+	}
+
+	airing := AiringSeasons(selectedSeries.Series)
+	var unmonitor []sonarr.Episode
+	for _, episode := range episodes {
+		if episode.HasFile && airing[episode.SeasonNumber] {
+			unmonitor = append(unmonitor, episode)
+		}
+	}
+	if len(files) > 0 {
+		// Monitoring is only narrowed once the files are really gone.
+		if err := DeleteEpisodeFiles(ctx, selectedSeries, files, unmonitor); err != nil {
+			fmt.Printf("Error deleting episodes: %v\n", err)
+			return
+		}
+	}
+
+	// Only future episodes are left monitored.
+	selectedSeries.Seasons = append([]sonarr.Season(nil), selectedSeries.Seasons...)
+	for i := range selectedSeries.Seasons {
+		selectedSeries.Seasons[i].Monitored = airing[selectedSeries.Seasons[i].SeasonNumber]
+	}
+	selectedSeries.Monitored = true
+	selectedSeries.MonitorNewItems = "all"
+	if err := sonarrClient.UpdateSeries(ctx, selectedSeries.Series); err != nil {
+		fmt.Printf("Error updating the monitoring of series '%s': %v\n", selectedSeries.Title, err)
+		return
+	}
+	plan.Annotate(fmt.Sprintf("Monitor only future episodes of '%s'", selectedSeries.Title), 0)
+	fmt.Printf(Green+"Series '%s' now only monitors future episodes in %s.\n"+Reset, selectedSeries.Title, sonarrName(selectedSeries.Instance))
+}
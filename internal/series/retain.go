@@ -0,0 +1,309 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Dates an episode window can be based on.
+const (
+	ByAirDate   = "airDate"
+	ByDateAdded = "dateAdded"
+)
+
+// RetainPolicy limits what a series keeps on disk. KeepSeasons keeps the most recent regular
+// seasons of every series; KeepEpisodes and KeepDays keep a rolling window of episode files
+// of daily series. Exactly one of them is set.
+type RetainPolicy struct {
+	KeepSeasons int
+	// IncludeSpecials allows season 0 to be deleted. It is never kept or counted otherwise.
+	IncludeSpecials bool
+
+	KeepEpisodes int
+	KeepDays     int
+	// By is the date the episode window is based on, ByAirDate or ByDateAdded.
+	By string
+}
+
+// validate checks that exactly one retention mode is selected.
+func (p RetainPolicy) validate() error {
+	modes := 0
+	for _, n := range []int{p.KeepSeasons, p.KeepEpisodes, p.KeepDays} {
+		if n < 0 {
+			return errors.New("--keep-seasons, --keep-episodes and --keep-days can't be negative")
+		}
+		if n > 0 {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return errors.New("set exactly one of --keep-seasons, --keep-episodes or --keep-days")
+	}
+	if p.By != ByAirDate && p.By != ByDateAdded {
+		return fmt.Errorf("invalid --by %q, expected %s or %s", p.By, ByAirDate, ByDateAdded)
+	}
+	return nil
+}
+
+// Retention is the outcome of a RetainPolicy for one series.
+type Retention struct {
+	Series Series
+	Keep   []int
+	Delete []int
+	Size   int64
+	// Skipped explains why nothing is deleted from a series with old seasons.
+	Skipped string
+}
+
+// Apply works out which seasons of a series the policy deletes. Series whose newest season is
+// still airing are skipped, since the season count is about to change.
+func (p RetainPolicy) Apply(s Series) Retention {
+	var regular []sonarr.Season
+	var specials *sonarr.Season
+	for i, season := range s.Seasons {
+		if season.SeasonNumber == 0 {
+			specials = &s.Seasons[i]
+			continue
+		}
+		regular = append(regular, season)
+	}
+	sort.Slice(regular, func(i, j int) bool {
+		return regular[i].SeasonNumber > regular[j].SeasonNumber
+	})
+
+	result := Retention{Series: s}
+	var old []sonarr.Season
+	for i, season := range regular {
+		if i < p.KeepSeasons {
+			result.Keep = append(result.Keep, season.SeasonNumber)
+		} else {
+			old = append(old, season)
+		}
+	}
+	if p.IncludeSpecials && specials != nil {
+		old = append(old, *specials)
+	}
+
+	for _, season := range old {
+		// Seasons without files that are already unmonitored need no change.
+		if season.Statistics.EpisodeFileCount == 0 && !season.Monitored {
+			continue
+		}
+		result.Delete = append(result.Delete, season.SeasonNumber)
+		result.Size += int64(season.Statistics.SizeOnDisk)
+	}
+	sort.Ints(result.Delete)
+	sort.Ints(result.Keep)
+
+	if len(result.Delete) > 0 && len(regular) > 0 && !regular[0].Statistics.NextAiring.IsZero() {
+		result.Skipped = fmt.Sprintf("season %d is still airing", regular[0].SeasonNumber)
+	}
+	return result
+}
+
+// HandleRetain applies a retention policy to every series. Without confirm it only prints a
+// report of what would be deleted.
+func HandleRetain(ctx context.Context, sonarrAPIKey string, policy RetainPolicy, confirm bool) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	// Get configuration
+	conf := config.GetConfig()
+	instances, err := conf.Sonarr(sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	r := render.New(conf.Output, conf.Columns)
+	for _, instance := range instances {
+		r.Infof("%s API Endpoint: %v\n", sonarrName(instance), instance.URL)
+	}
+
+	sonarrSeries, err := FetchSeries(ctx, instances)
+	if err != nil {
+		return err
+	}
+	if policy.KeepSeasons > 0 {
+		return retainSeasons(ctx, r, instances, sonarrSeries, policy, confirm)
+	}
+	return retainEpisodes(ctx, r, instances, sonarrSeries, policy, confirm)
+}
+
+// retainSeasons deletes and unmonitors the old seasons of every series.
+func retainSeasons(ctx context.Context, r *render.Renderer, instances []config.Instance, sonarrSeries []Series, policy RetainPolicy, confirm bool) error {
+	var retained []Retention
+	for _, s := range sonarrSeries {
+		if result := policy.Apply(s); len(result.Delete) > 0 {
+			retained = append(retained, result)
+		}
+	}
+	sort.SliceStable(retained, func(i, j int) bool {
+		return retained[i].Size > retained[j].Size
+	})
+	if len(retained) == 0 {
+		r.Infof("Every series already keeps at most %d seasons.\n", policy.KeepSeasons)
+		return nil
+	}
+
+	var records []render.Record
+	var total int64
+	for _, result := range retained {
+		action := "delete"
+		if result.Skipped != "" {
+			action = "skip: " + result.Skipped
+		} else {
+			total += result.Size
+		}
+		records = append(records, render.Record{
+			{Name: "instance", Value: result.Series.Instance.Name},
+			{Name: "id", Value: result.Series.ID},
+			{Name: "title", Value: result.Series.Title},
+			{Name: "keepSeasons", Value: joinInts(result.Keep)},
+			{Name: "deleteSeasons", Value: joinInts(result.Delete)},
+			{Name: "sizeOnDisk", Value: render.Bytes(result.Size)},
+			{Name: "action", Value: action},
+		})
+	}
+	if err := r.Render(records, withInstance(instances, "title", "keepSeasons", "deleteSeasons", "sizeOnDisk", "action")...); err != nil {
+		return err
+	}
+	r.Infof("%.2f GB can be freed.\n", units.ToGB(total))
+
+	if !confirm {
+		r.Infof(Yellow + "Dry run: nothing was deleted. Re-run with --yes to delete these seasons.\n" + Reset)
+		return nil
+	}
+	for _, result := range retained {
+		if result.Skipped == "" {
+			DeleteSeasons(ctx, nil, result.Series, result.Delete)
+		}
+	}
+	return nil
+}
+
+// Window is the outcome of an episode window for one daily series.
+type Window struct {
+	Series Series
+	Kept   int
+	// OldestKept is the date of the oldest episode file that stays on disk.
+	OldestKept time.Time
+	Delete     []sonarr.EpisodeFile
+	// Episodes are the episodes of the deleted files. They are unmonitored.
+	Episodes []sonarr.Episode
+	Size     int64
+}
+
+// Window works out which episode files of a daily series fall outside the policy's window.
+// Files holding several episodes count once and are dated by their latest episode.
+func (p RetainPolicy) Window(ctx context.Context, s Series, now time.Time) (Window, error) {
+	files, err := s.client.GetEpiosdeFilesForSeries(ctx, s.ID, nil)
+	if err != nil {
+		return Window{}, err
+	}
+	episodes, err := s.client.GetEpisodes(ctx, s.ID)
+	if err != nil {
+		return Window{}, err
+	}
+	episodesByFile := map[int][]sonarr.Episode{}
+	for _, episode := range episodes {
+		if episode.EpisodeFileID != 0 {
+			episodesByFile[episode.EpisodeFileID] = append(episodesByFile[episode.EpisodeFileID], episode)
+		}
+	}
+
+	date := func(file sonarr.EpisodeFile) time.Time {
+		if p.By == ByDateAdded {
+			return file.DateAdded
+		}
+		var aired time.Time
+		for _, episode := range episodesByFile[file.ID] {
+			if episode.AirDateUtc.After(aired) {
+				aired = episode.AirDateUtc
+			}
+		}
+		if aired.IsZero() {
+			return file.DateAdded
+		}
+		return aired
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return date(files[i]).After(date(files[j]))
+	})
+
+	result := Window{Series: s}
+	cutoff := now.AddDate(0, 0, -p.KeepDays)
+	for i, file := range files {
+		if (p.KeepEpisodes > 0 && i < p.KeepEpisodes) || (p.KeepDays > 0 && !date(file).Before(cutoff)) {
+			result.Kept++
+			result.OldestKept = date(file)
+			continue
+		}
+		result.Delete = append(result.Delete, file)
+		result.Episodes = append(result.Episodes, episodesByFile[file.ID]...)
+		result.Size += int64(file.Size)
+	}
+	return result, nil
+}
+
+// retainEpisodes deletes the episode files of daily series that fall outside the window and
+// unmonitors their episodes, so Sonarr doesn't grab them again.
+func retainEpisodes(ctx context.Context, r *render.Renderer, instances []config.Instance, sonarrSeries []Series, policy RetainPolicy, confirm bool) error {
+	var windows []Window
+	for _, s := range sonarrSeries {
+		if s.SeriesType != "daily" {
+			continue
+		}
+		result, err := policy.Window(ctx, s, time.Now())
+		if err != nil {
+			return fmt.Errorf("could not get episodes of '%s' from %s: %w", s.Title, sonarrName(s.Instance), err)
+		}
+		if len(result.Delete) > 0 {
+			windows = append(windows, result)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Size > windows[j].Size
+	})
+	if len(windows) == 0 {
+		r.Infof("No daily series has episode files outside the window.\n")
+		return nil
+	}
+
+	var records []render.Record
+	var total int64
+	for _, result := range windows {
+		total += result.Size
+		records = append(records, render.Record{
+			{Name: "instance", Value: result.Series.Instance.Name},
+			{Name: "id", Value: result.Series.ID},
+			{Name: "title", Value: result.Series.Title},
+			{Name: "keptFiles", Value: result.Kept},
+			{Name: "oldestKept", Value: result.OldestKept},
+			{Name: "deleteFiles", Value: len(result.Delete)},
+			{Name: "unmonitorEpisodes", Value: len(result.Episodes)},
+			{Name: "sizeOnDisk", Value: render.Bytes(result.Size)},
+		})
+	}
+	if err := r.Render(records, withInstance(instances, "title", "keptFiles", "oldestKept", "deleteFiles", "sizeOnDisk")...); err != nil {
+		return err
+	}
+	r.Infof("%.2f GB can be freed.\n", units.ToGB(total))
+
+	if !confirm {
+		r.Infof(Yellow + "Dry run: nothing was deleted. Re-run with --yes to delete these episode files.\n" + Reset)
+		return nil
+	}
+	for _, result := range windows {
+		if err := DeleteEpisodeFiles(ctx, result.Series, result.Delete, result.Episodes); err != nil {
+			fmt.Printf("Error deleting episodes: %v\n", err)
+		}
+	}
+	return nil
+}
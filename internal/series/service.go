@@ -2,9 +2,14 @@ package series
 
 import (
 	"bufio"
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
 	"flashbacklabsio/fcli/internal/clients/overseer"
 	"flashbacklabsio/fcli/internal/clients/sonarr"
 	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/render"
+	"flashbacklabsio/fcli/internal/units"
 	"fmt"
 	"os"
 	"sort"
@@ -24,13 +29,52 @@ const (
 	White   = "\033[97m"
 )
 
+// Series is a Sonarr series together with the instance it was found on.
+type Series struct {
+	sonarr.Series
+	Instance config.Instance
+	client   *sonarr.SonarrClient
+}
+
+// FetchSeries gets the series of every given Sonarr instance.
+func FetchSeries(ctx context.Context, instances []config.Instance) ([]Series, error) {
+	var all []Series
+	for _, instance := range instances {
+		client := sonarr.NewSonarrClient(instance.URL, instance.APIKey, instance.Options)
+		sonarrSeries, err := client.GetAllSeries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get series from %s: %w", sonarrName(instance), err)
+		}
+		for _, series := range sonarrSeries {
+			all = append(all, Series{Series: series, Instance: instance, client: client})
+		}
+	}
+	return all, nil
+}
+
+// sonarrName names an instance in messages, e.g. "Sonarr" or "Sonarr (uhd)".
+func sonarrName(instance config.Instance) string {
+	if instance.Name == config.DefaultInstance {
+		return "Sonarr"
+	}
+	return fmt.Sprintf("Sonarr (%s)", instance.Name)
+}
+
+// withInstance adds the instance column to the default columns when several instances are listed.
+func withInstance(instances []config.Instance, columns ...string) []string {
+	if len(instances) < 2 {
+		return columns
+	}
+	return append([]string{"instance"}, columns...)
+}
+
 func FindMediaItemByTvdbId(tvdbId int, mediaItems []overseer.Media) (*overseer.Media, error) {
 	for _, item := range mediaItems {
 		if item.TvdbId == tvdbId {
 			return &item, nil
 		}
 	}
-	return nil, fmt.Errorf("no matching MediaItem found for TvdbId %d", tvdbId)
+	return nil, fmt.Errorf("no matching MediaItem found for TvdbId %d; run \"fcli reconcile\" to find titles Overseer doesn't know about", tvdbId)
 }
 
 // Function to filter seasons with SizeOnDisk not equal to 0
@@ -44,143 +88,527 @@ func filterSeasons(seasons []sonarr.Season) []sonarr.Season {
 	return filteredSeasons
 }
 
+// SeriesRecord describes a series for listings, using the field names of the Sonarr API.
+func SeriesRecord(series Series) render.Record {
+	return render.Record{
+		{Name: "instance", Value: series.Instance.Name},
+		{Name: "id", Value: series.ID},
+		{Name: "title", Value: series.Title},
+		{Name: "year", Value: series.Year},
+		{Name: "status", Value: series.Status},
+		{Name: "airing", Value: Airing(series.Series)},
+		{Name: "seriesType", Value: series.SeriesType},
+		{Name: "network", Value: series.Network},
+		{Name: "seasonCount", Value: len(series.Seasons)},
+		{Name: "episodeFileCount", Value: series.Statistics.EpisodeFileCount},
+		{Name: "percentOfEpisodes", Value: series.Statistics.PercentOfEpisodes},
+		{Name: "sizeOnDisk", Value: render.Bytes(series.Statistics.SizeOnDisk)},
+		{Name: "path", Value: series.Path},
+		{Name: "added", Value: series.Added},
+		{Name: "nextAiring", Value: series.NextAiring},
+		{Name: "monitored", Value: series.Monitored},
+		{Name: "tvdbId", Value: series.TvdbID},
+		{Name: "tmdbId", Value: series.TmdbID},
+	}
+}
+
+// SeasonRecord describes a season for listings, using the field names of the Sonarr API.
+func SeasonRecord(season sonarr.Season) render.Record {
+	return render.Record{
+		{Name: "seasonNumber", Value: season.SeasonNumber},
+		{Name: "monitored", Value: season.Monitored},
+		{Name: "episodeFileCount", Value: season.Statistics.EpisodeFileCount},
+		{Name: "episodeCount", Value: season.Statistics.EpisodeCount},
+		{Name: "percentOfEpisodes", Value: season.Statistics.PercentOfEpisodes},
+		{Name: "sizeOnDisk", Value: render.Bytes(season.Statistics.SizeOnDisk)},
+	}
+}
+
+// HandleGet lists series sorted by size on disk. With seasons set, every series is followed by
+// its seasons: as a tree in tables and as a nested list in the other formats.
+func HandleGet(ctx context.Context, sonarrAPIKey string, limit int, skip int, seasons bool) error {
+	// Get configuration
+	conf := config.GetConfig()
+	instances, err := conf.Sonarr(sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+
+	r := render.New(conf.Output, conf.Columns)
+	for _, instance := range instances {
+		r.Infof("%s API Endpoint: %v\n", sonarrName(instance), instance.URL)
+	}
+	sonarrSeries, err := FetchSeries(ctx, instances)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(sonarrSeries, func(i, j int) bool {
+		return sonarrSeries[i].Statistics.SizeOnDisk > sonarrSeries[j].Statistics.SizeOnDisk
+	})
+
+	var records []render.Record
+	for i := skip; i < len(sonarrSeries) && i < skip+limit; i++ {
+		series := sonarrSeries[i]
+		record := append(render.Record{{Name: "index", Value: i + 1}}, SeriesRecord(series)...)
+		switch {
+		case !seasons:
+			records = append(records, record)
+		case r.IsTable():
+			records = append(records, record)
+			records = append(records, seasonTree(record, series.Seasons)...)
+		default:
+			var nested []render.Record
+			for _, season := range series.Seasons {
+				nested = append(nested, SeasonRecord(season))
+			}
+			records = append(records, append(record, render.Field{Name: "seasons", Value: nested}))
+		}
+	}
+	return r.Render(records, withInstance(instances, "index", "title", "airing", "nextAiring", "episodeFileCount", "percentOfEpisodes", "sizeOnDisk", "monitored")...)
+}
+
+// seasonTree builds table rows for the seasons of a series. The rows have the fields of the
+// series record so they line up with it; fields a season doesn't have are left blank.
+func seasonTree(parent render.Record, seasons []sonarr.Season) []render.Record {
+	var rows []render.Record
+	for i, season := range seasons {
+		branch := "├─"
+		if i == len(seasons)-1 {
+			branch = "└─"
+		}
+		values := map[string]any{
+			"title":             fmt.Sprintf(" %s Season %d", branch, season.SeasonNumber),
+			"nextAiring":        season.Statistics.NextAiring,
+			"episodeFileCount":  season.Statistics.EpisodeFileCount,
+			"percentOfEpisodes": season.Statistics.PercentOfEpisodes,
+			"sizeOnDisk":        render.Bytes(season.Statistics.SizeOnDisk),
+			"monitored":         season.Monitored,
+		}
+		row := make(render.Record, len(parent))
+		for j, f := range parent {
+			value, ok := values[f.Name]
+			if !ok {
+				value = ""
+			}
+			row[j] = render.Field{Name: f.Name, Value: value}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
 // HandleSeriesCommand is the entry point for the series command
 func HandleSeriesCommand() {
 	fmt.Println("Series management sub commands can be found here. Supply --help to see available series commands.")
 	// Add logic here
 }
-func HandleSearchAndDeleteSeries(sonarrAPIKey string, overseerAPIKey string, limit int) {
+func HandleSearchAndDeleteSeries(ctx context.Context, sonarrAPIKey string, overseerAPIKey string, overseerCleanup string, limit int, skip int) error {
 
-	// Initialize and get configuration
-	config.InitConfig()
+	// Get configuration
 	conf := config.GetConfig()
-	if len(sonarrAPIKey) > 0 {
-		conf.SonarrAPIKey = sonarrAPIKey
+	instances, err := conf.Sonarr(sonarrAPIKey)
+	if err != nil {
+		return err
 	}
 	if len(overseerAPIKey) > 0 {
 		conf.OverseerAPIKey = overseerAPIKey
 	}
-	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey)
-	sonarrClient := sonarr.NewSonarrClient(conf.SonarrURL, conf.SonarrAPIKey)
-	fmt.Printf("Sonarr API Endpoint: %v\n", conf.SonarrURL)
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	// Overseer is loaded late, so check the strategy before anything is selected.
+	if _, err := cleanup.ParseStrategy(conf.OverseerCleanup); err != nil {
+		return err
+	}
+	r := render.New(conf.Output, conf.Columns)
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+	for _, instance := range instances {
+		r.Infof("%s API Endpoint: %v\n", sonarrName(instance), instance.URL)
+	}
 
-	// Fetch and display series from Sonarr
-	sonarrSeries, err := sonarrClient.GetAllSeries()
+	// Fetch and display series from every selected Sonarr instance
+	sonarrSeries, err := FetchSeries(ctx, instances)
 	if err != nil {
-		fmt.Printf("Error fetching series: %v\n", err)
-		return
+		return err
+	}
+	if err := DisplaySeries(r, instances, sonarrSeries, limit, skip); err != nil {
+		return err
 	}
 
-	sort.Slice(sonarrSeries, func(i, j int) bool {
-		return sonarrSeries[i].Statistics.SizeOnDisk > sonarrSeries[j].Statistics.SizeOnDisk
-	})
+	// A single reader for every prompt, so input typed ahead isn't lost between them.
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(Green + "Select series numbers (comma-separated): " + Reset)
+	input, _ := reader.ReadString('\n')
+	selections := parseSelections(input, len(sonarrSeries))
+	if len(selections) == 0 {
+		fmt.Println("No series selected. Exiting.")
+		return nil
+	}
 
-	for i, series := range sonarrSeries {
-		if i > limit-1 {
-			break
+	// Overseer is only loaded once something is about to be deleted.
+	var overseerData *cleanup.Overseer
+	loadOverseer := func() error {
+		if overseerData != nil {
+			return nil
+		}
+		overseerData, err = cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+		return err
+	}
+	for _, seriesIndex := range selections {
+		selectedSeries := sonarrSeries[seriesIndex-1]
+		seasons := filterSeasons(selectedSeries.Seasons) //filter out series that aren't actually present.
+
+		r.Infof("\nSeasons of %s:\n", selectedSeries.Title)
+		var records []render.Record
+		for i, season := range seasons {
+			records = append(records, append(render.Record{{Name: "index", Value: i + 1}}, SeasonRecord(season)...))
+		}
+		if err := r.Render(records, "index", "seasonNumber", "episodeFileCount", "percentOfEpisodes", "sizeOnDisk", "monitored"); err != nil {
+			return err
 		}
 
-		fmt.Printf("%d: %s (%.2f GB)\n", i+1, series.Title, float64(series.Statistics.SizeOnDisk)/(1024*1024*1024))
+		fmt.Printf(Green+"Select seasons of '%s' (comma-separated, 0 = entire series, e<number> = pick episodes of a season, empty = skip): "+Reset, selectedSeries.Title)
+		input, _ := reader.ReadString('\n')
+		var seasonNumbers []int
+		var files []sonarr.EpisodeFile
+		wholeSeries := false
+		for _, token := range strings.Split(strings.TrimSpace(input), ",") {
+			token = strings.ToLower(strings.TrimSpace(token))
+			if token == "" {
+				continue
+			}
+			if token == "0" {
+				wholeSeries = true
+				continue
+			}
+			episodes := strings.HasPrefix(token, "e")
+			seasonIndex, err := strconv.Atoi(strings.TrimPrefix(token, "e"))
+			if err != nil || seasonIndex < 1 || seasonIndex > len(seasons) {
+				fmt.Printf("Invalid selection: %s\n", token)
+				continue
+			}
+			season := seasons[seasonIndex-1]
+			if !episodes {
+				seasonNumbers = append(seasonNumbers, season.SeasonNumber)
+				continue
+			}
+			picked, err := pickEpisodeFiles(ctx, r, reader, selectedSeries, season.SeasonNumber)
+			if err != nil {
+				return err
+			}
+			files = append(files, picked...)
+		}
+
+		if wholeSeries {
+			if Ongoing(selectedSeries.Series) {
+				// Deleting a show that still airs needs an explicit choice.
+				switch confirmOngoing(reader, selectedSeries) {
+				case ongoingKeepMonitoring:
+					KeepMonitoring(ctx, selectedSeries)
+					continue
+				case ongoingSkip:
+					fmt.Printf("Skipped deletion of series '%s'.\n", selectedSeries.Title)
+					continue
+				}
+			} else {
+				// Delete entire series
+				fmt.Printf(Yellow+"Are you sure you want to delete the entire series '%s' (%.2f GB)? (y/N): "+Reset, selectedSeries.Title, units.ToGB(int64(selectedSeries.Statistics.SizeOnDisk)))
+				if !confirm(reader) {
+					fmt.Printf("Skipped deletion of series '%s'.\n", selectedSeries.Title)
+					continue
+				}
+			}
+			if err := loadOverseer(); err != nil {
+				return err
+			}
+			DeleteSeries(ctx, overseerData, selectedSeries)
+			continue
+		}
+		if len(seasonNumbers) == 0 && len(files) == 0 {
+			fmt.Printf("Nothing selected for series '%s'.\n", selectedSeries.Title)
+			continue
+		}
+
+		// Delete selected seasons and episode files
+		what := describeSelection(seasonNumbers, files)
+		fmt.Printf(Yellow+"Are you sure you want to delete %s of '%s'? (y/N): "+Reset, what, selectedSeries.Title)
+		if !confirm(reader) {
+			fmt.Printf("Skipped deletion of %s of series '%s'.\n", what, selectedSeries.Title)
+			continue
+		}
+		if err := loadOverseer(); err != nil {
+			return err
+		}
+		DeleteEpisodes(ctx, overseerData, selectedSeries, seasonNumbers, files)
 	}
+	return nil
+}
 
-	// Ask user to select a series
-	fmt.Print(Green + "Select series number to view seasons or delete (0 to delete entire series): " + Reset)
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	seriesIndex, err := strconv.Atoi(strings.TrimSpace(input))
+// DisplaySeries sorts series by size and prints a numbered page of them.
+func DisplaySeries(r *render.Renderer, instances []config.Instance, sonarrSeries []Series, limit int, skip int) error {
+	sort.SliceStable(sonarrSeries, func(i, j int) bool {
+		return sonarrSeries[i].Statistics.SizeOnDisk > sonarrSeries[j].Statistics.SizeOnDisk
+	})
 
-	if err != nil || seriesIndex < 0 || seriesIndex > len(sonarrSeries) {
-		fmt.Printf("Invalid selection: %s\n", input)
-		return
+	var records []render.Record
+	for i := skip; i < len(sonarrSeries) && i < skip+limit; i++ {
+		records = append(records, append(render.Record{{Name: "index", Value: i + 1}}, SeriesRecord(sonarrSeries[i])...))
+	}
+	r.Infof("Series:\n")
+	return r.Render(records, withInstance(instances, "index", "title", "airing", "sizeOnDisk")...)
+}
+
+// pickEpisodeFiles lists the episode files of a season and asks which of them to delete.
+func pickEpisodeFiles(ctx context.Context, r *render.Renderer, reader *bufio.Reader, selectedSeries Series, seasonNumber int) ([]sonarr.EpisodeFile, error) {
+	files, err := selectedSeries.EpisodeFiles(ctx, seasonNumber)
+	if err != nil {
+		return nil, err
 	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].RelativePath < files[j].RelativePath
+	})
 
-	if seriesIndex == 0 {
-		fmt.Println("No series selected. Exiting.")
-		return
+	r.Infof("Episode files of season %d:\n", seasonNumber)
+	var records []render.Record
+	for i, file := range files {
+		records = append(records, render.Record{
+			{Name: "index", Value: i + 1},
+			{Name: "id", Value: file.ID},
+			{Name: "relativePath", Value: file.RelativePath},
+			{Name: "size", Value: render.Bytes(file.Size)},
+			{Name: "dateAdded", Value: file.DateAdded},
+			{Name: "quality", Value: file.Quality.Quality.Name},
+		})
+	}
+	if err := r.Render(records, "index", "relativePath", "size", "dateAdded", "quality"); err != nil {
+		return nil, err
 	}
 
-	selectedSeries := sonarrSeries[seriesIndex-1]
-	selectedSeries.Seasons = filterSeasons(selectedSeries.Seasons) //filter out series that aren't actually present.
+	fmt.Printf(Green+"Select episode files of season %d (comma-separated): "+Reset, seasonNumber)
+	input, _ := reader.ReadString('\n')
+	var picked []sonarr.EpisodeFile
+	for _, index := range parseSelections(input, len(files)) {
+		picked = append(picked, files[index-1])
+	}
+	return picked, nil
+}
 
-	fmt.Printf("Selected series: %s\n", selectedSeries.Title)
+// parseSelections parses a comma-separated list of numbers between 1 and count. Invalid and
+// repeated entries are reported and skipped.
+func parseSelections(input string, count int) []int {
+	var selections []int
+	seen := map[int]bool{}
+	for _, selection := range strings.Split(strings.TrimSpace(input), ",") {
+		if strings.TrimSpace(selection) == "" {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(selection))
+		if err != nil || index < 1 || index > count || seen[index] {
+			fmt.Printf("Invalid selection: %s\n", selection)
+			continue
+		}
+		seen[index] = true
+		selections = append(selections, index)
+	}
+	return selections
+}
+
+// confirm reads a yes/no answer. Anything but "y" means no.
+func confirm(reader *bufio.Reader) bool {
+	input, _ := reader.ReadString('\n')
+	return strings.ToLower(strings.TrimSpace(input)) == "y"
+}
 
-	fmt.Println("Seasons:")
-	for i, season := range selectedSeries.Seasons {
-		fmt.Printf("%d: Season %d (%.2f GB)\n", i+1, season.SeasonNumber, float64(season.Statistics.SizeOnDisk)/(1024*1024*1024))
+// describeSelection names the selected seasons and episode files in messages, e.g.
+// "season(s) 1, 2 and 3 episode file(s)".
+func describeSelection(seasonNumbers []int, files []sonarr.EpisodeFile) string {
+	var parts []string
+	if len(seasonNumbers) > 0 {
+		parts = append(parts, "season(s) "+joinInts(seasonNumbers))
+	}
+	if len(files) > 0 {
+		parts = append(parts, fmt.Sprintf("%d episode file(s)", len(files)))
+	}
+	return strings.Join(parts, " and ")
+}
 
+// DeleteSeries removes a series and its files from its Sonarr instance and cleans up Overseer.
+func DeleteSeries(ctx context.Context, overseerData *cleanup.Overseer, selectedSeries Series) {
+	err := selectedSeries.client.DeleteSeries(ctx, selectedSeries.ID)
+	if err != nil {
+		fmt.Printf("Error deleting series: %v\n", err)
+	} else {
+		plan.Annotate(fmt.Sprintf("Delete series '%s' from %s", selectedSeries.Title, sonarrName(selectedSeries.Instance)), int64(selectedSeries.Statistics.SizeOnDisk))
+		fmt.Printf(Green+"Series '%s' successfully deleted from %s.\n"+Reset, selectedSeries.Title, sonarrName(selectedSeries.Instance))
 	}
 
-	// Ask user to select a season or delete the entire series
-	fmt.Print(Green + "Select season number to delete or enter 0 to delete the entire series: " + Reset)
-	seasonInput, _ := reader.ReadString('\n')
-	seasonIndex, err := strconv.Atoi(strings.TrimSpace(seasonInput))
-	if err != nil || seasonIndex < 0 || seasonIndex > len(selectedSeries.Seasons) {
-		fmt.Printf("Invalid selection: %s\n", seasonInput)
+	// Delete corresponding media item or requests from Overseer
+	media, err := FindMediaItemByTvdbId(selectedSeries.TvdbID, overseerData.Media)
+	if err != nil {
+		fmt.Println(err.Error())
 		return
 	}
+	overseerData.Remove(ctx, selectedSeries.Instance, *media, selectedSeries.Title)
+}
 
-	if seasonIndex == 0 {
-		// Delete entire series
-		fmt.Printf(Yellow+"Are you sure you want to delete the entire series '%s'? (y/N): "+Reset, selectedSeries.Title)
-		confirmInput, _ := reader.ReadString('\n')
-		confirmInput = strings.TrimSpace(confirmInput)
-		if strings.ToLower(confirmInput) != "y" {
-			fmt.Printf("Skipped deletion of series '%s'.\n", selectedSeries.Title)
-		} else {
-			err = sonarrClient.DeleteSeries(selectedSeries.ID)
-			if err != nil {
-				fmt.Printf("Error deleting series: %v\n", err)
-			} else {
-				fmt.Printf(Green+"Series '%s' successfully deleted from Sonarr.\n"+Reset, selectedSeries.Title)
-			}
+// DeleteSeasons deletes the episode files of the given seasons and unmonitors those seasons in Sonarr.
+// overseerData may be nil to leave Overseer unchanged.
+func DeleteSeasons(ctx context.Context, overseerData *cleanup.Overseer, selectedSeries Series, seasonNumbers []int) {
+	DeleteEpisodes(ctx, overseerData, selectedSeries, seasonNumbers, nil)
+}
 
-			// Delete corresponding request from Overseer
-			mediaItems, err := overseerClient.GetMedia()
-			if err != nil {
-				fmt.Printf("Error fetching media: %v\n", err)
-			} else {
-				media, err := FindMediaItemByTvdbId(selectedSeries.TvdbID, mediaItems)
-				if err != nil {
-					fmt.Println(err.Error())
-				} else {
-					err = overseerClient.DeleteMedia(media.Id)
-					if err != nil {
-						fmt.Printf("Error deleting request from Overseer: %v\n", err)
-					} else {
-						fmt.Printf(Green+"Request '%s' successfully deleted from Overseer.\n"+Reset, selectedSeries.Title)
-					}
-				}
-			}
+// DeleteEpisodes deletes whole seasons and individual episode files of a series in one bulk call,
+// then unmonitors the deleted seasons in one series update. The episodes of files picked on their
+// own are unmonitored in one call, leaving the monitoring of their season unchanged. Deleted
+// seasons are also removed from the title's Overseer requests and status unless overseerData is nil.
+func DeleteEpisodes(ctx context.Context, overseerData *cleanup.Overseer, selectedSeries Series, seasonNumbers []int, files []sonarr.EpisodeFile) {
+	sonarrClient := selectedSeries.client
+	selected := map[int]bool{}
+	for _, seasonNumber := range seasonNumbers {
+		selected[seasonNumber] = true
+	}
+
+	var toDelete []sonarr.EpisodeFile
+	seen := map[int]bool{}
+	if len(seasonNumbers) > 0 {
+		episodeFiles, err := sonarrClient.GetEpiosdeFilesForSeries(ctx, selectedSeries.ID, nil)
+		if err != nil {
+			fmt.Printf("Error getting season episode files: %v\n", err)
+			return
 		}
-	} else {
-		// Delete selected episodefiles
-		selectedSeason := &selectedSeries.Seasons[seasonIndex-1]
-		selectedSeason.Monitored = false
-		fmt.Printf(Yellow+"Are you sure you want to delete Season %d of '%s'? (y/N): "+Reset, selectedSeason.SeasonNumber, selectedSeries.Title)
-		confirmInput, _ := reader.ReadString('\n')
-		confirmInput = strings.TrimSpace(confirmInput)
-		if strings.ToLower(confirmInput) != "y" {
-			fmt.Printf("Skipped deletion of Season %d of series '%s'.\n", selectedSeason.SeasonNumber, selectedSeries.Title)
-		} else {
-			episodeFiles, err := sonarrClient.GetEpiosdeFilesForSeries(selectedSeries.ID, &selectedSeason.SeasonNumber)
-			if err != nil {
-				fmt.Printf("Error getting season episode files: %v\n", err)
+		for _, file := range episodeFiles {
+			if selected[file.SeasonNumber] {
+				toDelete = append(toDelete, file)
+				seen[file.ID] = true
 			}
-			err = sonarrClient.DeleteEpisodeFiles(episodeFiles)
-			if err != nil {
-				fmt.Printf("Error deleting episodes: %v\n", err)
-			} else {
-				fmt.Printf(Green+"Season %d of series '%s' successfully deleted from Sonarr.\n"+Reset, selectedSeason.SeasonNumber, selectedSeries.Title)
+		}
+	}
+	picked := map[int]bool{}
+	for _, file := range files {
+		if !seen[file.ID] {
+			toDelete = append(toDelete, file)
+			seen[file.ID] = true
+			picked[file.ID] = true
+		}
+	}
+	// Episodes lose their file ID once the file is deleted, so they are looked up first.
+	var episodeIDs []int
+	if len(picked) > 0 {
+		episodes, err := sonarrClient.GetEpisodes(ctx, selectedSeries.ID)
+		if err != nil {
+			fmt.Printf("Error getting episodes: %v\n", err)
+			return
+		}
+		for _, episode := range episodes {
+			if picked[episode.EpisodeFileID] {
+				episodeIDs = append(episodeIDs, episode.ID)
 			}
+		}
+	}
+	var size int64
+	for _, file := range toDelete {
+		size += int64(file.Size)
+	}
 
+	what := describeSelection(seasonNumbers, files)
+	if len(toDelete) > 0 {
+		if err := sonarrClient.DeleteEpisodeFiles(ctx, toDelete); err != nil {
+			// The files are still on disk, so Sonarr and Overseer should keep tracking them.
+			fmt.Printf("Error deleting episodes: %v\n", err)
+			return
 		}
-		// Update the series to unmonitor the deleted season.
-		err := sonarrClient.UpdateSeries(selectedSeries)
-		if err != nil {
-			fmt.Printf("Error removing season %d monitoring. This means the series will be downloaded automatically again. ERROR: %v\n", selectedSeason.SeasonNumber, err)
-		} else {
-			fmt.Printf(Green+"Season %d of series '%s' successfully unmonitored in Sonarr.\n"+Reset, selectedSeason.SeasonNumber, selectedSeries.Title)
+		plan.Annotate(fmt.Sprintf("Delete %s of '%s'", what, selectedSeries.Title), size)
+		fmt.Printf(Green+"Deleted %s of series '%s' from %s (%.2f GB).\n"+Reset, what, selectedSeries.Title, sonarrName(selectedSeries.Instance), units.ToGB(size))
+	}
+	unmonitorEpisodes(ctx, selectedSeries, episodeIDs)
+	if len(seasonNumbers) == 0 {
+		return
+	}
+
+	// Update the series to unmonitor the deleted seasons.
+	selectedSeries.Seasons = append([]sonarr.Season(nil), selectedSeries.Seasons...)
+	for i := range selectedSeries.Seasons {
+		if selected[selectedSeries.Seasons[i].SeasonNumber] {
+			selectedSeries.Seasons[i].Monitored = false
 		}
+	}
+	err := sonarrClient.UpdateSeries(ctx, selectedSeries.Series)
+	if err != nil {
+		fmt.Printf("Error removing season %s monitoring. This means the series will be downloaded automatically again. ERROR: %v\n", joinInts(seasonNumbers), err)
+	} else {
+		plan.Annotate(fmt.Sprintf("Unmonitor season(s) %s of '%s'", joinInts(seasonNumbers), selectedSeries.Title), 0)
+		fmt.Printf(Green+"Season(s) %s of series '%s' successfully unmonitored in %s.\n"+Reset, joinInts(seasonNumbers), selectedSeries.Title, sonarrName(selectedSeries.Instance))
+	}
+
+	if overseerData == nil {
+		return
+	}
+	media, err := FindMediaItemByTvdbId(selectedSeries.TvdbID, overseerData.Media)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	overseerData.RemoveSeasons(ctx, selectedSeries.Instance, *media, selectedSeries.Title, seasonNumbers)
+}
+
+// DeleteEpisodeFiles deletes episode files of a series in one bulk call and unmonitors their
+// episodes in one call, leaving the monitoring of their seasons unchanged. It returns an error
+// when the files could not be deleted; a failure to unmonitor is only printed.
+func DeleteEpisodeFiles(ctx context.Context, selectedSeries Series, files []sonarr.EpisodeFile, episodes []sonarr.Episode) error {
+	var size int64
+	for _, file := range files {
+		size += int64(file.Size)
+	}
+	if err := selectedSeries.client.DeleteEpisodeFiles(ctx, files); err != nil {
+		return err
+	}
+	plan.Annotate(fmt.Sprintf("Delete %d episode file(s) of '%s'", len(files), selectedSeries.Title), size)
+	fmt.Printf(Green+"Deleted %d episode file(s) of series '%s' from %s (%.2f GB).\n"+Reset, len(files), selectedSeries.Title, sonarrName(selectedSeries.Instance), units.ToGB(size))
+
+	var episodeIDs []int
+	for _, episode := range episodes {
+		episodeIDs = append(episodeIDs, episode.ID)
+	}
+	unmonitorEpisodes(ctx, selectedSeries, episodeIDs)
+	return nil
+}
+
+// unmonitorEpisodes unmonitors episodes of a series in one call. A failure is only printed.
+func unmonitorEpisodes(ctx context.Context, selectedSeries Series, episodeIDs []int) {
+	if len(episodeIDs) == 0 {
+		return
+	}
+	if err := selectedSeries.client.SetEpisodesMonitored(ctx, episodeIDs, false); err != nil {
+		fmt.Printf("Error removing episode monitoring. This means the episodes will be downloaded automatically again. ERROR: %v\n", err)
+		return
+	}
+	plan.Annotate(fmt.Sprintf("Unmonitor %d episode(s) of '%s'", len(episodeIDs), selectedSeries.Title), 0)
+	fmt.Printf(Green+"%d episode(s) of series '%s' successfully unmonitored in %s.\n"+Reset, len(episodeIDs), selectedSeries.Title, sonarrName(selectedSeries.Instance))
+}
+
+// EpisodeFiles returns the episode files of one season of a series.
+func (s Series) EpisodeFiles(ctx context.Context, seasonNumber int) ([]sonarr.EpisodeFile, error) {
+	return s.client.GetEpiosdeFilesForSeries(ctx, s.ID, &seasonNumber)
+}
+
+// TagLabels returns the labels of the tags defined on the series' instance, by tag ID.
+func (s Series) TagLabels(ctx context.Context) (map[int]string, error) {
+	tags, err := s.client.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[int]string{}
+	for _, tag := range tags {
+		labels[tag.ID] = tag.Label
+	}
+	return labels, nil
+}
 
+// joinInts formats a list of numbers as a comma-separated string.
+func joinInts(numbers []int) string {
+	var parts []string
+	for _, n := range numbers {
+		parts = append(parts, strconv.Itoa(n))
 	}
+	return strings.Join(parts, ", ")
 }
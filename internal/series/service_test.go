@@ -0,0 +1,129 @@
+package series
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/config"
+	"slices"
+	"testing"
+)
+
+var overseerLoad = []string{"GET /media?skip=0&sort=added&take=100", "GET /request?skip=0&take=100"}
+
+// load fetches the series of a Sonarr replaying dir and the Overseer data replaying testdata.
+func load(t *testing.T, dir string) (Series, *rest.ReplayHandler, *cleanup.Overseer, *rest.ReplayHandler) {
+	t.Helper()
+	ctx := context.Background()
+	sonarrURL, sonarrCalls := rest.NewTestReplay(t, dir, "sonarr")
+	overseerURL, overseerCalls := rest.NewTestReplay(t, "testdata", "overseer")
+
+	found, err := FetchSeries(ctx, []config.Instance{{Name: "main", URL: sonarrURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("got %d series, want 1", len(found))
+	}
+	overseerData, err := cleanup.Load(ctx, overseer.NewOverseerClient(overseerURL, "", rest.Options{}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found[0], sonarrCalls, overseerData, overseerCalls
+}
+
+func TestDeleteEpisodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		dir          string
+		seasons      []int
+		files        []sonarr.EpisodeFile
+		wantSonarr   []string
+		wantOverseer []string
+	}{
+		{
+			name:       "deleted",
+			dir:        "testdata/delete-episodes",
+			seasons:    []int{1},
+			wantSonarr: []string{"GET /series", "GET /episodefile?seriesId=10", "DELETE /episodefile/bulk", "PUT /series/10"},
+			// Season 2 is still available, so the request keeps it and the title is partial.
+			wantOverseer: slices.Concat(overseerLoad, []string{"PUT /request/703", "POST /media/7/partial"}),
+		},
+		{
+			// The files are still on disk, so the season stays monitored and Overseer is left alone.
+			name:         "delete failed",
+			dir:          "testdata/delete-episodes-failed",
+			seasons:      []int{1},
+			wantSonarr:   []string{"GET /series", "GET /episodefile?seriesId=10", "DELETE /episodefile/bulk"},
+			wantOverseer: overseerLoad,
+		},
+		{
+			// A picked file's episode is unmonitored so Sonarr doesn't download it again; its season stays monitored.
+			name:         "file picked",
+			dir:          "testdata/delete-episodes-picked",
+			files:        []sonarr.EpisodeFile{{ID: 102, SeriesID: 10, SeasonNumber: 2}},
+			wantSonarr:   []string{"GET /series", "GET /episode?seriesId=10", "DELETE /episodefile/bulk", "PUT /episode/monitor"},
+			wantOverseer: overseerLoad,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, sonarrCalls, overseerData, overseerCalls := load(t, tt.dir)
+
+			DeleteEpisodes(context.Background(), overseerData, selected, tt.seasons, tt.files)
+
+			if got := sonarrCalls.Served(); !slices.Equal(got, tt.wantSonarr) {
+				t.Errorf("Sonarr calls = %q, want %q", got, tt.wantSonarr)
+			}
+			if got := overseerCalls.Served(); !slices.Equal(got, tt.wantOverseer) {
+				t.Errorf("Overseer calls = %q, want %q", got, tt.wantOverseer)
+			}
+		})
+	}
+}
+
+func TestDeleteSeries(t *testing.T) {
+	selected, sonarrCalls, overseerData, overseerCalls := load(t, "testdata/delete-series")
+
+	DeleteSeries(context.Background(), overseerData, selected)
+
+	wantSonarr := []string{"GET /series", "DELETE /series/10?deleteFiles=true"}
+	if got := sonarrCalls.Served(); !slices.Equal(got, wantSonarr) {
+		t.Errorf("Sonarr calls = %q, want %q", got, wantSonarr)
+	}
+	wantOverseer := slices.Concat(overseerLoad, []string{"DELETE /media/7"})
+	if got := overseerCalls.Served(); !slices.Equal(got, wantOverseer) {
+		t.Errorf("Overseer calls = %q, want %q", got, wantOverseer)
+	}
+}
+
+func TestDeleteEpisodeFiles(t *testing.T) {
+	files := []sonarr.EpisodeFile{{ID: 100, SeriesID: 10, SeasonNumber: 1}, {ID: 101, SeriesID: 10, SeasonNumber: 1}}
+	episodes := []sonarr.Episode{{ID: 1000}, {ID: 1001}}
+	tests := []struct {
+		name    string
+		dir     string
+		want    []string
+		wantErr bool
+	}{
+		{"deleted", "testdata/delete-episode-files", []string{"GET /series", "DELETE /episodefile/bulk", "PUT /episode/monitor"}, false},
+		// The caller needs the error to leave the monitoring of the series alone.
+		{"delete failed", "testdata/delete-episodes-failed", []string{"GET /series", "DELETE /episodefile/bulk"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, sonarrCalls, _, _ := load(t, tt.dir)
+
+			err := DeleteEpisodeFiles(context.Background(), selected, files, episodes)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeleteEpisodeFiles() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := sonarrCalls.Served(); !slices.Equal(got, tt.want) {
+				t.Errorf("Sonarr calls = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
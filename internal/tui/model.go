@@ -0,0 +1,429 @@
+package tui
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	activeTabStyle = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	tabStyle       = lipgloss.NewStyle().Padding(0, 1)
+	headerStyle    = lipgloss.NewStyle().Bold(true).Underline(true)
+	cursorStyle    = lipgloss.NewStyle().Reverse(true)
+	selectedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	dimStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	warningStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+	errorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// filesLoadedMsg carries the episode files of a season fetched in the background.
+type filesLoadedMsg struct {
+	series       series.Series
+	seasonNumber int
+	files        []sonarr.EpisodeFile
+	err          error
+}
+
+// model is the state of the TUI. Every tab keeps a stack of views for drilling down.
+type model struct {
+	ctx      context.Context
+	tabs     [][]*view
+	active   int
+	selected map[string]item
+	order    []string // selection keys in the order they were picked
+
+	filtering  bool
+	confirming bool
+	confirmed  bool
+	// protecting is set while the user decides what to do with selected series that still air.
+	protecting bool
+	// keepMonitoring deletes only the files of those series and keeps them monitored.
+	keepMonitoring bool
+	loading        bool
+	err            error
+	width          int
+	height         int
+}
+
+func newModel(ctx context.Context, tabs []*view) model {
+	m := model{ctx: ctx, selected: map[string]item{}, width: 100, height: 30}
+	for _, tab := range tabs {
+		m.tabs = append(m.tabs, []*view{tab})
+	}
+	return m
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+// view returns the view shown in the active tab.
+func (m model) view() *view {
+	stack := m.tabs[m.active]
+	return stack[len(stack)-1]
+}
+
+// pageSize is the number of table rows that fit on the screen.
+func (m model) pageSize() int {
+	return max(m.height-7, 1)
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case filesLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		s := msg.series
+		v := newView(fmt.Sprintf("Season %d", msg.seasonNumber), fileColumns, fileRows(s, msg.files))
+		v.series = &s
+		m.tabs[m.active] = append(m.tabs[m.active], v)
+		return m, nil
+	case tea.KeyMsg:
+		if m.confirming {
+			return m.updateConfirm(msg)
+		}
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.protecting {
+		switch msg.String() {
+		case "D":
+			m.confirmed = true
+			return m, tea.Quit
+		case "k", "K":
+			m.confirmed, m.keepMonitoring = true, true
+			return m, tea.Quit
+		case "n", "N", "esc", "q":
+			m.confirming, m.protecting = false, false
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case "y", "Y":
+		// Series that still air need a second, explicit decision.
+		if len(m.ongoing()) > 0 {
+			m.protecting = true
+			return m, nil
+		}
+		m.confirmed = true
+		return m, tea.Quit
+	case "n", "N", "esc", "q":
+		m.confirming = false
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// ongoing returns the selected whole series that still get new episodes.
+func (m model) ongoing() []item {
+	var items []item
+	for _, it := range m.effective() {
+		if it.kind == "series" && series.Ongoing(it.series.Series) {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.view()
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyEsc:
+		m.filtering = false
+		v.filter = ""
+	case tea.KeyBackspace:
+		if len(v.filter) > 0 {
+			runes := []rune(v.filter)
+			v.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyRunes, tea.KeySpace:
+		v.filter += string(msg.Runes)
+	}
+	v.cursor, v.offset = 0, 0
+	v.refresh()
+	return m, nil
+}
+
+func (m model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.view()
+	m.err = nil
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "tab", "right", "l":
+		m.active = (m.active + 1) % len(m.tabs)
+	case "shift+tab", "left", "h":
+		m.active = (m.active + len(m.tabs) - 1) % len(m.tabs)
+	case "1", "2", "3":
+		if i := int(msg.String()[0] - '1'); i < len(m.tabs) {
+			m.active = i
+		}
+	case "up", "k":
+		v.cursor = max(v.cursor-1, 0)
+	case "down", "j":
+		v.cursor = min(v.cursor+1, max(len(v.visible)-1, 0))
+	case "pgup":
+		v.cursor = max(v.cursor-m.pageSize(), 0)
+	case "pgdown":
+		v.cursor = min(v.cursor+m.pageSize(), max(len(v.visible)-1, 0))
+	case "home", "g":
+		v.cursor = 0
+	case "end", "G":
+		v.cursor = max(len(v.visible)-1, 0)
+	case "/":
+		m.filtering = true
+	case "s":
+		v.sortBy = (v.sortBy + 1) % len(sortKeys)
+		v.refresh()
+	case "S":
+		v.asc = !v.asc
+		v.refresh()
+	case " ", "x":
+		if r, ok := v.current(); ok {
+			m.toggle(r.item)
+		}
+		v.cursor = min(v.cursor+1, max(len(v.visible)-1, 0))
+	case "enter":
+		return m.drillDown()
+	case "esc", "backspace":
+		if v.filter != "" {
+			v.filter = ""
+			v.refresh()
+		} else if len(m.tabs[m.active]) > 1 {
+			m.tabs[m.active] = m.tabs[m.active][:len(m.tabs[m.active])-1]
+		}
+	case "d":
+		if len(m.selected) > 0 {
+			m.confirming = true
+		}
+	}
+	return m, nil
+}
+
+// toggle selects or deselects an item.
+func (m *model) toggle(it item) {
+	key := it.key()
+	if _, ok := m.selected[key]; ok {
+		delete(m.selected, key)
+		for i, k := range m.order {
+			if k == key {
+				m.order = append(m.order[:i], m.order[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	m.selected[key] = it
+	m.order = append(m.order, key)
+}
+
+// drillDown opens the seasons of a series or the episode files of a season.
+func (m model) drillDown() (tea.Model, tea.Cmd) {
+	r, ok := m.view().current()
+	if !ok || !r.drill || m.loading {
+		return m, nil
+	}
+	switch r.item.kind {
+	case "series":
+		s := r.item.series
+		v := newView(s.Title, seasonColumns, seasonRows(s))
+		v.series = &s
+		m.tabs[m.active] = append(m.tabs[m.active], v)
+		return m, nil
+	case "season":
+		m.loading = true
+		s, seasonNumber := r.item.series, r.item.seasonNumber
+		ctx := m.ctx
+		return m, func() tea.Msg {
+			files, err := s.EpisodeFiles(ctx, seasonNumber)
+			return filesLoadedMsg{series: s, seasonNumber: seasonNumber, files: files, err: err}
+		}
+	}
+	return m, nil
+}
+
+// effective returns the selected items in selection order, leaving out seasons and episode
+// files whose series or season is selected as a whole.
+func (m model) effective() []item {
+	var items []item
+	for _, key := range m.order {
+		it := m.selected[key]
+		if it.kind == "season" || it.kind == "file" {
+			if _, ok := m.selected[seriesKey(it.series)]; ok {
+				continue
+			}
+		}
+		if it.kind == "file" {
+			if _, ok := m.selected[fmt.Sprintf("%s/season/%d", seriesKey(it.series), it.seasonNumber)]; ok {
+				continue
+			}
+		}
+		items = append(items, it)
+	}
+	return items
+}
+
+// freed is the space the current selection would free.
+func (m model) freed() int64 {
+	var total int64
+	for _, it := range m.effective() {
+		total += it.size
+	}
+	return total
+}
+
+func (m model) View() string {
+	if m.confirming {
+		return m.confirmView()
+	}
+
+	var b strings.Builder
+	for i, tab := range m.tabs {
+		if i == m.active {
+			b.WriteString(activeTabStyle.Render(tab[0].title))
+		} else {
+			b.WriteString(tabStyle.Render(tab[0].title))
+		}
+	}
+	b.WriteString("\n")
+
+	v := m.view()
+	var crumbs []string
+	for _, s := range m.tabs[m.active] {
+		crumbs = append(crumbs, s.title)
+	}
+	order := "desc"
+	if v.asc {
+		order = "asc"
+	}
+	b.WriteString(dimStyle.Render(fmt.Sprintf("%s  ·  sort: %s %s  ·  %d of %d", strings.Join(crumbs, " › "), sortKeys[v.sortBy], order, len(v.visible), len(v.rows))))
+	b.WriteString("\n")
+
+	m.writeTable(&b, v)
+
+	if m.filtering || v.filter != "" {
+		b.WriteString(fmt.Sprintf("Filter: %s", v.filter))
+		if m.filtering {
+			b.WriteString("█")
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString("\n")
+	}
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("Error: "+m.err.Error()) + "\n")
+	} else if m.loading {
+		b.WriteString("Loading episode files...\n")
+	} else {
+		b.WriteString(selectedStyle.Render(fmt.Sprintf("%d selected, %.2f GB would be freed", len(m.effective()), units.ToGB(m.freed()))) + "\n")
+	}
+	b.WriteString(dimStyle.Render("space select · enter open · esc back · / filter · s sort · S reverse · tab switch · d delete · q quit"))
+	return b.String()
+}
+
+// writeTable renders the visible page of a view. The first column takes the remaining width.
+func (m model) writeTable(b *strings.Builder, v *view) {
+	widths := make([]int, len(v.columns))
+	for i, c := range v.columns {
+		widths[i] = runewidth.StringWidth(c)
+	}
+	for _, idx := range v.visible {
+		for i, cell := range v.rows[idx].cells {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+	// Two characters for the selection marker and two spaces between columns.
+	rest := 2
+	for i := 1; i < len(widths); i++ {
+		rest += widths[i] + 2
+	}
+	widths[0] = max(min(widths[0], m.width-rest-2), 10)
+
+	line := func(cells []string) string {
+		var parts []string
+		for i, cell := range cells {
+			parts = append(parts, runewidth.FillRight(runewidth.Truncate(cell, widths[i], "…"), widths[i]))
+		}
+		return strings.Join(parts, "  ")
+	}
+
+	b.WriteString("  " + headerStyle.Render(line(v.columns)) + "\n")
+
+	page := m.pageSize()
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+page {
+		v.offset = v.cursor - page + 1
+	}
+	for i := v.offset; i < len(v.visible) && i < v.offset+page; i++ {
+		r := v.rows[v.visible[i]]
+		marker := "  "
+		if _, ok := m.selected[r.item.key()]; ok {
+			marker = "✓ "
+		}
+		text := marker + line(r.cells)
+		switch {
+		case i == v.cursor:
+			text = cursorStyle.Render(text)
+		case marker != "  ":
+			text = selectedStyle.Render(text)
+		}
+		b.WriteString(text + "\n")
+	}
+	for i := len(v.visible) - v.offset; i < page; i++ {
+		b.WriteString("\n")
+	}
+}
+
+// confirmView is the single confirmation screen listing everything that will be deleted.
+func (m model) confirmView() string {
+	var b strings.Builder
+	b.WriteString(warningStyle.Render("The following items will be deleted:") + "\n\n")
+	items := m.effective()
+	page := max(m.height-6, 1)
+	for i, it := range items {
+		if i == page {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("... and %d more", len(items)-page)) + "\n")
+			break
+		}
+		line := fmt.Sprintf("  %-8s %s  %s", it.kind, runewidth.Truncate(it.label, max(m.width-25, 20), "…"), formatSize(it.size))
+		if it.kind == "series" && series.Ongoing(it.series.Series) {
+			line += warningStyle.Render("  ⚠ " + series.OngoingReason(it.series.Series))
+		}
+		b.WriteString(line + "\n")
+	}
+	if m.protecting {
+		b.WriteString("\n" + warningStyle.Render(fmt.Sprintf("%d selected series are still airing. Press D to delete them anyway, k to delete their files but keep monitoring future episodes, or n to cancel.", len(m.ongoing()))))
+		return b.String()
+	}
+	b.WriteString("\n" + warningStyle.Render(fmt.Sprintf("%d items, %.2f GB will be freed. Delete them? (y/N)", len(items), units.ToGB(m.freed()))))
+	return b.String()
+}
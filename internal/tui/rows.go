@@ -0,0 +1,288 @@
+package tui
+
+import (
+	"cmp"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/series"
+	"flashbacklabsio/fcli/internal/units"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// item is something that can be selected for deletion.
+type item struct {
+	kind         string // "movie", "series", "season", "file" or "request"
+	label        string
+	size         int64
+	movie        movies.Movie
+	series       series.Series
+	seasonNumber int
+	file         sonarr.EpisodeFile
+	request      overseer.Request
+}
+
+// key identifies an item across views. Sonarr and Radarr IDs are only unique within an instance.
+func (i item) key() string {
+	switch i.kind {
+	case "movie":
+		return fmt.Sprintf("movie/%s/%d", i.movie.Instance.Name, i.movie.ID)
+	case "series":
+		return seriesKey(i.series)
+	case "season":
+		return fmt.Sprintf("%s/season/%d", seriesKey(i.series), i.seasonNumber)
+	case "file":
+		return fmt.Sprintf("%s/file/%d", seriesKey(i.series), i.file.ID)
+	default:
+		return fmt.Sprintf("request/%d", i.request.ID)
+	}
+}
+
+func seriesKey(s series.Series) string {
+	return fmt.Sprintf("series/%s/%d", s.Instance.Name, s.ID)
+}
+
+// row is one line of a view. Cells are already formatted; the other fields are used for sorting.
+type row struct {
+	cells   []string
+	title   string
+	size    int64
+	added   time.Time
+	rating  float64
+	quality string
+	item    item
+	// drill is set for rows that can be opened with enter.
+	drill bool
+}
+
+// sortKeys are the fields a view can be sorted by, in the order "s" cycles through them.
+var sortKeys = []string{"size", "added", "rating", "quality", "title"}
+
+// view is one table: a top-level tab or a drill-down level below it.
+type view struct {
+	title   string
+	columns []string
+	rows    []row
+	visible []int // indexes into rows after filtering and sorting
+	cursor  int
+	offset  int
+	sortBy  int
+	asc     bool
+	filter  string
+
+	// set for the seasons and episode file views of a series
+	series *series.Series
+}
+
+func newView(title string, columns []string, rows []row) *view {
+	v := &view{title: title, columns: columns, rows: rows}
+	v.refresh()
+	return v
+}
+
+// refresh applies the filter and sort order and keeps the cursor in range.
+func (v *view) refresh() {
+	v.visible = v.visible[:0]
+	filter := strings.ToLower(v.filter)
+	for i, r := range v.rows {
+		if filter == "" || strings.Contains(strings.ToLower(strings.Join(r.cells, " ")), filter) {
+			v.visible = append(v.visible, i)
+		}
+	}
+
+	key := sortKeys[v.sortBy]
+	sort.SliceStable(v.visible, func(a, b int) bool {
+		c := compare(v.rows[v.visible[a]], v.rows[v.visible[b]], key)
+		if v.asc {
+			return c < 0
+		}
+		return c > 0
+	})
+
+	if v.cursor >= len(v.visible) {
+		v.cursor = len(v.visible) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+}
+
+// compare orders two rows by a sort key.
+func compare(a, b row, key string) int {
+	switch key {
+	case "size":
+		return cmp.Compare(a.size, b.size)
+	case "added":
+		return a.added.Compare(b.added)
+	case "rating":
+		return cmp.Compare(a.rating, b.rating)
+	case "quality":
+		return strings.Compare(strings.ToLower(a.quality), strings.ToLower(b.quality))
+	default:
+		return strings.Compare(strings.ToLower(a.title), strings.ToLower(b.title))
+	}
+}
+
+// current returns the row under the cursor.
+func (v *view) current() (row, bool) {
+	if len(v.visible) == 0 {
+		return row{}, false
+	}
+	return v.rows[v.visible[v.cursor]], true
+}
+
+func formatSize(size int64) string {
+	return fmt.Sprintf("%.2f GB", units.ToGB(size))
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatRating(rating float64) string {
+	if rating < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", rating)
+}
+
+// movieRows builds the Movies tab.
+func movieRows(radarrMovies []movies.Movie) []row {
+	var rows []row
+	for _, movie := range radarrMovies {
+		added, _ := time.Parse(time.RFC3339, movie.Added)
+		rating := -1.0
+		if movie.Ratings.IMDb.Value > 0 {
+			rating = float64(movie.Ratings.IMDb.Value)
+		} else if movie.Ratings.TMDB.Value > 0 {
+			rating = float64(movie.Ratings.TMDB.Value)
+		}
+		size := int64(movie.Statistics.SizeOnDisk)
+		quality := movie.MovieFile.Quality.Quality.Name
+		rows = append(rows, row{
+			cells:   []string{movie.Title, fmt.Sprint(movie.Year), movie.Instance.Name, formatSize(size), formatDate(added), formatRating(rating), quality},
+			title:   movie.Title,
+			size:    size,
+			added:   added,
+			rating:  rating,
+			quality: quality,
+			item:    item{kind: "movie", label: movie.Title, size: size, movie: movie},
+		})
+	}
+	return rows
+}
+
+var movieColumns = []string{"Title", "Year", "Instance", "Size", "Added", "Rating", "Quality"}
+
+// seriesRows builds the Series tab.
+func seriesRows(sonarrSeries []series.Series) []row {
+	var rows []row
+	for _, s := range sonarrSeries {
+		rating := -1.0
+		if s.Ratings.Value > 0 {
+			rating = float64(s.Ratings.Value)
+		}
+		size := int64(s.Statistics.SizeOnDisk)
+		rows = append(rows, row{
+			cells:  []string{s.Title, fmt.Sprint(s.Year), s.Instance.Name, formatSize(size), formatDate(s.Added), formatRating(rating), s.Status, fmt.Sprint(len(s.Seasons))},
+			title:  s.Title,
+			size:   size,
+			added:  s.Added,
+			rating: rating,
+			item:   item{kind: "series", label: s.Title, size: size, series: s},
+			drill:  true,
+		})
+	}
+	return rows
+}
+
+var seriesColumns = []string{"Title", "Year", "Instance", "Size", "Added", "Rating", "Status", "Seasons"}
+
+// seasonRows builds the seasons of a series.
+func seasonRows(s series.Series) []row {
+	var rows []row
+	for _, season := range s.Seasons {
+		size := int64(season.Statistics.SizeOnDisk)
+		title := fmt.Sprintf("Season %d", season.SeasonNumber)
+		rows = append(rows, row{
+			cells:  []string{title, fmt.Sprintf("%d/%d", season.Statistics.EpisodeFileCount, season.Statistics.EpisodeCount), fmt.Sprintf("%.0f%%", season.Statistics.PercentOfEpisodes), formatSize(size), fmt.Sprint(season.Monitored)},
+			title:  title,
+			size:   size,
+			rating: -1,
+			item:   item{kind: "season", label: fmt.Sprintf("%s - %s", s.Title, title), size: size, series: s, seasonNumber: season.SeasonNumber},
+			drill:  true,
+		})
+	}
+	return rows
+}
+
+var seasonColumns = []string{"Season", "Files", "Complete", "Size", "Monitored"}
+
+// fileRows builds the episode files of one season.
+func fileRows(s series.Series, files []sonarr.EpisodeFile) []row {
+	var rows []row
+	for _, file := range files {
+		size := int64(file.Size)
+		quality := file.Quality.Quality.Name
+		rows = append(rows, row{
+			cells:   []string{file.RelativePath, formatSize(size), formatDate(file.DateAdded), quality},
+			title:   file.RelativePath,
+			size:    size,
+			added:   file.DateAdded,
+			rating:  -1,
+			quality: quality,
+			item:    item{kind: "file", label: fmt.Sprintf("%s - %s", s.Title, file.RelativePath), size: size, series: s, seasonNumber: file.SeasonNumber, file: file},
+		})
+	}
+	return rows
+}
+
+var fileColumns = []string{"File", "Size", "Added", "Quality"}
+
+// requestRows builds the Requests tab. Titles and sizes come from the matching Radarr or Sonarr item,
+// since Overseer only knows TMDB and TVDB IDs.
+func requestRows(requests []overseer.Request, radarrMovies []movies.Movie, sonarrSeries []series.Series) []row {
+	type known struct {
+		title string
+		size  int64
+	}
+	moviesByTmdb := map[int]known{}
+	for _, m := range radarrMovies {
+		moviesByTmdb[m.TMDBID] = known{m.Title, int64(m.Statistics.SizeOnDisk)}
+	}
+	seriesByTvdb := map[int]known{}
+	for _, s := range sonarrSeries {
+		seriesByTvdb[s.TvdbID] = known{s.Title, int64(s.Statistics.SizeOnDisk)}
+	}
+
+	var rows []row
+	for _, request := range requests {
+		media, ok := moviesByTmdb[request.Media.TmdbId]
+		if request.Media.MediaType == "tv" {
+			media, ok = seriesByTvdb[request.Media.TvdbId]
+		}
+		title := media.title
+		if !ok {
+			title = fmt.Sprintf("tmdb:%d", request.Media.TmdbId)
+		}
+		user := request.RequestedBy.Name()
+		rows = append(rows, row{
+			cells:  []string{fmt.Sprint(request.ID), title, request.Media.MediaType, overseer.RequestStatusName(request.Status), user, formatDate(request.CreatedAt), formatSize(media.size), fmt.Sprint(request.Is4K)},
+			title:  title,
+			size:   media.size,
+			added:  request.CreatedAt,
+			rating: -1,
+			// Deleting a request frees no disk space.
+			item: item{kind: "request", label: fmt.Sprintf("Request %d (%s by %s)", request.ID, title, user), request: request},
+		})
+	}
+	return rows
+}
+
+var requestColumns = []string{"ID", "Title", "Type", "Status", "Requested By", "Added", "Size", "4K"}
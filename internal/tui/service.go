@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"flashbacklabsio/fcli/internal/cleanup"
+	"flashbacklabsio/fcli/internal/clients/overseer"
+	"flashbacklabsio/fcli/internal/clients/rest"
+	"flashbacklabsio/fcli/internal/clients/sonarr"
+	"flashbacklabsio/fcli/internal/config"
+	"flashbacklabsio/fcli/internal/movies"
+	"flashbacklabsio/fcli/internal/plan"
+	"flashbacklabsio/fcli/internal/series"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	Reset  = "\033[0m"
+	Red    = "\033[31m"
+	Green  = "\033[32m"
+	Yellow = "\033[33m"
+)
+
+// HandleTUI loads movies, series and requests, lets the user pick what to delete in a
+// full-screen UI and then runs the regular delete logic for the confirmed selection.
+func HandleTUI(ctx context.Context, radarrAPIKey, sonarrAPIKey, overseerAPIKey, overseerCleanup string) error {
+	// Get configuration
+	conf := config.GetConfig()
+	radarrInstances, sonarrInstances, err := conf.Both(radarrAPIKey, sonarrAPIKey)
+	if err != nil {
+		return err
+	}
+	if len(overseerAPIKey) > 0 {
+		conf.OverseerAPIKey = overseerAPIKey
+	}
+	if len(overseerCleanup) > 0 {
+		conf.OverseerCleanup = overseerCleanup
+	}
+	overseerClient := overseer.NewOverseerClient(conf.OverseerURL, conf.OverseerAPIKey, conf.OverseerOptions)
+
+	fmt.Println("Loading movies, series and requests...")
+	radarrMovies, err := movies.FetchMovies(ctx, radarrInstances)
+	if err != nil {
+		return err
+	}
+	sonarrSeries, err := series.FetchSeries(ctx, sonarrInstances)
+	if err != nil {
+		return err
+	}
+	overseerData, err := cleanup.Load(ctx, overseerClient, conf.OverseerCleanup)
+	if err != nil {
+		return err
+	}
+
+	// Services without any configured or selected instance get no tab.
+	var tabs []*view
+	if len(radarrInstances) > 0 {
+		tabs = append(tabs, newView("Movies", movieColumns, movieRows(radarrMovies)))
+	}
+	if len(sonarrInstances) > 0 {
+		tabs = append(tabs, newView("Series", seriesColumns, seriesRows(sonarrSeries)))
+	}
+	tabs = append(tabs, newView("Requests", requestColumns, requestRows(overseerData.Requests, radarrMovies, sonarrSeries)))
+	m := newModel(ctx, tabs)
+	final, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if err != nil {
+		return err
+	}
+	result := final.(model)
+	if !result.confirmed {
+		fmt.Println("Nothing was deleted.")
+		return nil
+	}
+
+	apply(ctx, overseerData, result.effective(), result.keepMonitoring)
+	return nil
+}
+
+// apply deletes the confirmed items. Seasons and episode files are grouped per series so each
+// series gets a single bulk delete and a single monitoring update. With keepMonitoring, series
+// that still air only lose their files.
+func apply(ctx context.Context, overseerData *cleanup.Overseer, items []item, keepMonitoring bool) {
+	var seriesOrder []string
+	seriesByKey := map[string]series.Series{}
+	seasons := map[string][]int{}
+	files := map[string][]sonarr.EpisodeFile{}
+	group := func(s series.Series) string {
+		key := seriesKey(s)
+		if _, ok := seriesByKey[key]; !ok {
+			seriesOrder = append(seriesOrder, key)
+			seriesByKey[key] = s
+		}
+		return key
+	}
+
+	for _, it := range items {
+		switch it.kind {
+		case "movie":
+			movies.DeleteMovie(ctx, overseerData, it.movie)
+		case "series":
+			if keepMonitoring && series.Ongoing(it.series.Series) {
+				series.KeepMonitoring(ctx, it.series)
+			} else {
+				series.DeleteSeries(ctx, overseerData, it.series)
+			}
+		case "season":
+			key := group(it.series)
+			seasons[key] = append(seasons[key], it.seasonNumber)
+		case "file":
+			key := group(it.series)
+			files[key] = append(files[key], it.file)
+		}
+	}
+	for _, key := range seriesOrder {
+		series.DeleteEpisodes(ctx, overseerData, seriesByKey[key], seasons[key], files[key])
+	}
+
+	for _, it := range items {
+		if it.kind != "request" {
+			continue
+		}
+		if err := overseerData.Client.DeleteRequest(ctx, it.request.ID); rest.IsNotFound(err) {
+			fmt.Printf(Yellow+"%s was already removed from Overseer.\n"+Reset, it.label)
+		} else if err != nil {
+			fmt.Println(Red + err.Error() + Reset)
+		} else {
+			plan.Annotate(fmt.Sprintf("Delete Overseer %s", it.label), 0)
+			fmt.Printf(Green+"%s was successfully deleted from Overseer.\n"+Reset, it.label)
+		}
+	}
+}
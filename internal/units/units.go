@@ -0,0 +1,86 @@
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GB is the number of bytes in a gigabyte as displayed throughout fcli.
+const GB = 1024 * 1024 * 1024
+
+// MB is the number of bytes in a megabyte, the unit of Radarr and Sonarr quality sizes.
+const MB = 1024 * 1024
+
+var suffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"TB", 1024 * GB},
+	{"GB", GB},
+	{"MB", 1024 * 1024},
+	{"KB", 1024},
+	{"T", 1024 * GB},
+	{"G", GB},
+	{"M", 1024 * 1024},
+	{"K", 1024},
+	{"B", 1},
+}
+
+// ParseBytes parses a human readable size such as "500GB" or "1.5TB" into bytes.
+// A value without a unit is interpreted as gigabytes.
+func ParseBytes(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "IB"), "I")
+	multiplier := float64(GB)
+	for _, u := range suffixes {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			multiplier = u.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a value like 500GB or 1.5TB", value)
+	}
+	return int64(n * multiplier), nil
+}
+
+// ToGB converts a size in bytes to gigabytes.
+func ToGB[T int | int64](bytes T) float64 {
+	return float64(bytes) / GB
+}
+
+// Day is the length of a day as used by ParseAge.
+const Day = 24 * time.Hour
+
+var ageUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"h", time.Hour},
+	{"d", Day},
+	{"w", 7 * Day},
+	{"y", 365 * Day},
+}
+
+// ParseAge parses an age such as "30d", "2w", "1y" or "12h". A value without a unit is
+// interpreted as days.
+func ParseAge(value string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	unit := Day
+	for _, u := range ageUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			unit = u.unit
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age %q, expected a value like 30d, 2w or 1y", value)
+	}
+	return time.Duration(n * float64(unit)), nil
+}